@@ -0,0 +1,372 @@
+package paginator
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+)
+
+// Cursor directions.
+const (
+	CursorNext = "next"
+	CursorPrev = "prev"
+)
+
+var (
+	// ErrInvalidCursor is returned when a cursor cannot be decoded.
+	ErrInvalidCursor = errors.New("invalid cursor")
+
+	// ErrFirstAndLast is returned when both Relay first and last args are set.
+	ErrFirstAndLast = errors.New("first and last cannot be used together")
+
+	// ErrNegativeCount is returned when Relay first or last args are negative.
+	ErrNegativeCount = errors.New("first and last cannot be negative")
+)
+
+// PageInfo represents a GraphQL Relay connection's pageInfo.
+type PageInfo struct {
+	HasNextPage     bool   `json:"hasNextPage"`
+	HasPreviousPage bool   `json:"hasPreviousPage"`
+	StartCursor     string `json:"startCursor"`
+	EndCursor       string `json:"endCursor"`
+}
+
+// KeyValue is a sort column and its value in a composite keyset cursor.
+type KeyValue struct {
+	Column string
+	Value  interface{}
+}
+
+// cursor is the payload encoded into opaque cursor strings. For composite
+// keys, Key has the values and Cols the column names in the same order.
+type cursor struct {
+	Key  interface{} `json:"k"`
+	Cols []string    `json:"c,omitempty"`
+	Dir  string      `json:"d"`
+}
+
+// newCursor returns a cursor for the given key, splitting a composite
+// []KeyValue key into its values and column names.
+func newCursor(key interface{}, dir string) cursor {
+	kv, ok := key.([]KeyValue)
+	if !ok {
+		return cursor{Key: key, Dir: dir}
+	}
+
+	var (
+		vals = make([]interface{}, len(kv))
+		cols = make([]string, len(kv))
+	)
+	for i, k := range kv {
+		vals[i] = k.Value
+		cols[i] = k.Column
+	}
+	return cursor{Key: vals, Cols: cols, Dir: dir}
+}
+
+// NewFromCursor returns a new pagination Set for keyset (cursor) pagination
+// from an opaque cursor generated by NextCursor() or PrevCursor(). Depending
+// on the cursor's direction, the last-seen key is set on Set.After or
+// Set.Before. The offset based values are left as they are for the first page.
+// If cur is empty, a regular offset based Set for the first page is returned
+// so that the same code path can be used.
+func (p *Paginator) NewFromCursor(cur string, perPage int) (Set, error) {
+	if cur == "" {
+		return p.New(p.firstPage(), perPage), nil
+	}
+
+	c, err := decodeCursor(cur)
+	if err != nil {
+		return Set{}, err
+	}
+
+	s := p.New(p.firstPage(), perPage)
+	s.Cursor = cur
+	s.keyCols = c.Cols
+	switch c.Dir {
+	case CursorNext:
+		s.After = c.Key
+	case CursorPrev:
+		s.Before = c.Key
+		s.backward = true
+	default:
+		return Set{}, ErrInvalidCursor
+	}
+
+	return s, nil
+}
+
+// NextCursor returns an opaque cursor for the page following the current one.
+// lastKey is the sort key (eg: id) of the last item on the current page. For
+// keysets over multiple sort columns, lastKey is a []KeyValue with the columns
+// and values in the sort order, eg:
+// []KeyValue{{Column: "created_at", Value: t}, {Column: "id", Value: id}}.
+func (s *Set) NextCursor(lastKey interface{}) string {
+	return encodeCursor(newCursor(lastKey, CursorNext))
+}
+
+// PrevCursor returns an opaque cursor for the page preceding the current one.
+// firstKey is the sort key (eg: id) of the first item on the current page.
+func (s *Set) PrevCursor(firstKey interface{}) string {
+	return encodeCursor(newCursor(firstKey, CursorPrev))
+}
+
+func encodeCursor(c cursor) string {
+	b, err := json.Marshal(c)
+	if err != nil {
+		return ""
+	}
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func decodeCursor(cur string) (cursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(cur)
+	if err != nil {
+		return cursor{}, ErrInvalidCursor
+	}
+
+	// Decode numbers as json.Number to not lose precision on large
+	// integer keys.
+	var c cursor
+	d := json.NewDecoder(bytes.NewReader(b))
+	d.UseNumber()
+	if err := d.Decode(&c); err != nil {
+		return cursor{}, ErrInvalidCursor
+	}
+	c.Key = normalizeNumber(c.Key)
+
+	return c, nil
+}
+
+// normalizeNumber converts a json.Number into an int64 or a float64. The
+// values in composite keys ([]interface{}) are converted recursively.
+func normalizeNumber(v interface{}) interface{} {
+	if vals, ok := v.([]interface{}); ok {
+		for i, v := range vals {
+			vals[i] = normalizeNumber(v)
+		}
+		return vals
+	}
+
+	n, ok := v.(json.Number)
+	if !ok {
+		return v
+	}
+
+	if i, err := n.Int64(); err == nil {
+		return i
+	}
+	if f, err := n.Float64(); err == nil {
+		return f
+	}
+	return n.String()
+}
+
+// SearchAfter returns an opaque token with the sort values of the last item
+// on the current page for Elasticsearch style search_after pagination.
+func (s *Set) SearchAfter(sortValues []interface{}) string {
+	b, err := json.Marshal(sortValues)
+	if err != nil {
+		return ""
+	}
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// NewFromSearchAfter decodes a token generated by SearchAfter() and returns
+// the sort values to be passed as search_after to the search query along
+// with a new pagination Set. The sort values are also set on Set.After.
+func (p *Paginator) NewFromSearchAfter(token string, perPage int) ([]interface{}, Set, error) {
+	b, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, Set{}, ErrInvalidCursor
+	}
+
+	var vals []interface{}
+	d := json.NewDecoder(bytes.NewReader(b))
+	d.UseNumber()
+	if err := d.Decode(&vals); err != nil || vals == nil {
+		return nil, Set{}, ErrInvalidCursor
+	}
+	for i, v := range vals {
+		vals[i] = normalizeNumber(v)
+	}
+
+	s := p.New(p.firstPage(), perPage)
+	s.Cursor = token
+	s.After = vals
+	return vals, s, nil
+}
+
+// NewFromTimeCursor returns a new pagination Set for time based pagination
+// where items older than before are to be fetched,
+// eg: WHERE created_at < $before ORDER BY created_at DESC. before is
+// set on Set.Before as a time.Time and the offset based values stay zero.
+func (p *Paginator) NewFromTimeCursor(before time.Time, perPage int) Set {
+	s := p.New(p.firstPage(), perPage)
+	s.Before = before
+	return s
+}
+
+// NextTimeCursor returns an opaque cursor for the page following the current
+// one in time based pagination. oldest is the timestamp of the oldest item on
+// the current page. The cursor can be decoded with ParseTimeCursor().
+func (s *Set) NextTimeCursor(oldest time.Time) string {
+	return encodeCursor(cursor{Key: oldest.UTC().Format(time.RFC3339Nano), Dir: CursorNext})
+}
+
+// ParseTimeCursor decodes a cursor generated by NextTimeCursor() and returns
+// the timestamp to be passed to NewFromTimeCursor().
+func ParseTimeCursor(cur string) (time.Time, error) {
+	c, err := decodeCursor(cur)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	v, ok := c.Key.(string)
+	if !ok {
+		return time.Time{}, ErrInvalidCursor
+	}
+	t, err := time.Parse(time.RFC3339Nano, v)
+	if err != nil {
+		return time.Time{}, ErrInvalidCursor
+	}
+	return t, nil
+}
+
+// NewFromRelayArgs returns a new pagination Set from GraphQL Relay connection
+// arguments. first or last is the number of items per page and after or
+// before is an opaque cursor whose key is set on Set.After or Set.Before.
+// With last, items are to be fetched backwards, ie: the last items before
+// Set.Before.
+func (p *Paginator) NewFromRelayArgs(first, last *int, after, before *string) (Set, error) {
+	if first != nil && last != nil {
+		return Set{}, ErrFirstAndLast
+	}
+
+	var perPage int
+	if first != nil {
+		perPage = *first
+	} else if last != nil {
+		perPage = *last
+	}
+	if perPage < 0 {
+		return Set{}, ErrNegativeCount
+	}
+
+	s := p.New(p.firstPage(), perPage)
+	s.backward = last != nil
+	if after != nil {
+		c, err := decodeCursor(*after)
+		if err != nil {
+			return Set{}, err
+		}
+		s.Cursor = *after
+		s.After = c.Key
+		s.keyCols = c.Cols
+	}
+	if before != nil {
+		c, err := decodeCursor(*before)
+		if err != nil {
+			return Set{}, err
+		}
+		s.Cursor = *before
+		s.Before = c.Key
+		s.keyCols = c.Cols
+	}
+
+	return s, nil
+}
+
+// SetKeys sets the sort keys (eg: id) of the first and the last items on the
+// current page. They are used to generate the cursors in PageInfo().
+func (s *Set) SetKeys(firstKey, lastKey interface{}) {
+	s.firstKey = firstKey
+	s.lastKey = lastKey
+}
+
+// PageInfo returns the Relay pageInfo for the Set. SetKeys() should be called
+// to generate the start and end cursors and SetHasMore() to indicate if there
+// are more items in the direction of pagination.
+func (s *Set) PageInfo() PageInfo {
+	var out PageInfo
+	if s.backward {
+		out.HasPreviousPage = s.HasMore
+		out.HasNextPage = s.Before != nil
+	} else {
+		out.HasNextPage = s.HasNext()
+		out.HasPreviousPage = s.After != nil || s.HasPrev()
+	}
+
+	if s.firstKey != nil {
+		out.StartCursor = s.PrevCursor(s.firstKey)
+	}
+	if s.lastKey != nil {
+		out.EndCursor = s.NextCursor(s.lastKey)
+	}
+	return out
+}
+
+// Cursors returns the opaque cursors for the pages preceding and following
+// the current one. SetKeys() should be called with the keys of the first and
+// the last items on the current page and SetHasMore() to indicate if there
+// are more items in the direction of pagination. At either edge, the
+// respective cursor is empty.
+func (s *Set) Cursors() (string, string) {
+	var (
+		pi   = s.PageInfo()
+		prev string
+		next string
+	)
+	if pi.HasPreviousPage {
+		prev = pi.StartCursor
+	}
+	if pi.HasNextPage {
+		next = pi.EndCursor
+	}
+	return prev, next
+}
+
+// KeysetWhere returns an SQL tuple comparison expression for keyset pagination
+// over the given sort columns and the key values from the cursor, eg:
+// (created_at, id) > (?, ?) for After and < for Before. For multiple columns,
+// the cursor must have been generated with a []KeyValue key over the same
+// columns in the same order. If there is no cursor or the columns or the
+// number of values don't match, an empty string and nil are returned.
+func (s *Set) KeysetWhere(columns []string) (string, []interface{}) {
+	key, op := s.After, ">"
+	if key == nil {
+		key, op = s.Before, "<"
+	}
+	if key == nil || len(columns) == 0 {
+		return "", nil
+	}
+
+	vals, ok := key.([]interface{})
+	if !ok {
+		vals = []interface{}{key}
+	}
+	if len(vals) != len(columns) {
+		return "", nil
+	}
+
+	// Bind the values only to the columns they were generated for.
+	if len(columns) > 1 || s.keyCols != nil {
+		if len(s.keyCols) != len(columns) {
+			return "", nil
+		}
+		for i, c := range columns {
+			if s.keyCols[i] != c {
+				return "", nil
+			}
+		}
+	}
+
+	if len(columns) == 1 {
+		return columns[0] + " " + op + " ?", vals
+	}
+	ph := strings.TrimSuffix(strings.Repeat("?, ", len(columns)), ", ")
+	return "(" + strings.Join(columns, ", ") + ") " + op + " (" + ph + ")", vals
+}
@@ -0,0 +1,279 @@
+package paginator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCursor(t *testing.T) {
+	p := New(Default())
+
+	// Integer key.
+	s := p.New(1, 20)
+	cur := s.NextCursor(12345678901234)
+	s, err := p.NewFromCursor(cur, 20)
+	assert.NoError(t, err)
+	assert.Equal(t, s.Cursor, cur)
+	assert.Equal(t, s.After, int64(12345678901234))
+	assert.Nil(t, s.Before)
+	assert.Equal(t, s.Offset, 0)
+	assert.Equal(t, s.Limit, 20)
+
+	// String key in the reverse direction.
+	cur = s.PrevCursor("abc")
+	s, err = p.NewFromCursor(cur, 500)
+	assert.NoError(t, err)
+	assert.Equal(t, s.Before, "abc")
+	assert.Nil(t, s.After)
+	assert.Equal(t, s.Limit, 50)
+
+	// Float key.
+	s, err = p.NewFromCursor(s.NextCursor(1.5), 10)
+	assert.NoError(t, err)
+	assert.Equal(t, s.After, 1.5)
+
+	// Invalid cursors.
+	_, err = p.NewFromCursor("!!!", 10)
+	assert.Equal(t, err, ErrInvalidCursor)
+	_, err = p.NewFromCursor("bm90anNvbg", 10)
+	assert.Equal(t, err, ErrInvalidCursor)
+	_, err = p.NewFromCursor(encodeCursor(cursor{Key: 1, Dir: "x"}), 10)
+	assert.Equal(t, err, ErrInvalidCursor)
+}
+
+func TestRelay(t *testing.T) {
+	p := New(Default())
+	n := func(i int) *int { return &i }
+	str := func(s string) *string { return &s }
+
+	// First page.
+	s, err := p.NewFromRelayArgs(n(5), nil, nil, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, s.Limit, 5)
+	assert.Nil(t, s.After)
+	assert.Nil(t, s.Before)
+
+	s.SetKeys(1, 5)
+	s.SetHasMore(true)
+	pi := s.PageInfo()
+	assert.True(t, pi.HasNextPage)
+	assert.False(t, pi.HasPreviousPage)
+
+	// Next page with the end cursor.
+	s, err = p.NewFromRelayArgs(n(5), nil, str(pi.EndCursor), nil)
+	assert.NoError(t, err)
+	assert.Equal(t, s.After, int64(5))
+	s.SetKeys(6, 10)
+	pi = s.PageInfo()
+	assert.False(t, pi.HasNextPage)
+	assert.True(t, pi.HasPreviousPage)
+
+	// Backwards with the start cursor.
+	s, err = p.NewFromRelayArgs(nil, n(3), nil, str(pi.StartCursor))
+	assert.NoError(t, err)
+	assert.Equal(t, s.Before, int64(6))
+	assert.Equal(t, s.Limit, 3)
+	s.SetHasMore(true)
+	pi = s.PageInfo()
+	assert.True(t, pi.HasPreviousPage)
+	assert.True(t, pi.HasNextPage)
+	assert.Equal(t, pi.StartCursor, "")
+	assert.Equal(t, pi.EndCursor, "")
+
+	// No args.
+	s, err = p.NewFromRelayArgs(nil, nil, nil, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, s.Limit, 10)
+
+	// Conflicting and invalid args.
+	_, err = p.NewFromRelayArgs(n(5), n(5), nil, nil)
+	assert.Equal(t, err, ErrFirstAndLast)
+	_, err = p.NewFromRelayArgs(n(-1), nil, nil, nil)
+	assert.Equal(t, err, ErrNegativeCount)
+	_, err = p.NewFromRelayArgs(n(5), nil, str("!!!"), nil)
+	assert.Equal(t, err, ErrInvalidCursor)
+}
+
+func TestSearchAfter(t *testing.T) {
+	p := New(Default())
+	s := p.New(1, 10)
+
+	tok := s.SearchAfter([]interface{}{1577836800000, "doc#42", 3.5, true, nil})
+	vals, s, err := p.NewFromSearchAfter(tok, 20)
+	assert.Nil(t, err)
+	assert.Equal(t, vals, []interface{}{int64(1577836800000), "doc#42", 3.5, true, nil})
+	assert.Equal(t, s.PerPage, 20)
+	assert.Equal(t, s.Cursor, tok)
+	assert.Equal(t, s.After, vals)
+
+	// Invalid tokens.
+	for _, tok := range []string{"!!", "", s.NextCursor(1)} {
+		_, _, err = p.NewFromSearchAfter(tok, 20)
+		assert.ErrorIs(t, err, ErrInvalidCursor, tok)
+	}
+}
+
+func TestTimeCursor(t *testing.T) {
+	p := New(Default())
+
+	ts := time.Date(2020, 1, 2, 3, 4, 5, 6, time.UTC)
+	s := p.NewFromTimeCursor(ts, 20)
+	assert.Equal(t, s.Before, ts)
+	assert.Equal(t, s.PerPage, 20)
+	assert.Equal(t, s.Offset, 0)
+
+	// Round trip.
+	oldest := ts.Add(-time.Hour)
+	cur := s.NextTimeCursor(oldest)
+	b, err := ParseTimeCursor(cur)
+	assert.Nil(t, err)
+	assert.True(t, b.Equal(oldest))
+
+	s = p.NewFromTimeCursor(b, 20)
+	assert.Equal(t, s.Before, b)
+
+	// Invalid cursors.
+	for _, cur := range []string{"!!", s.NextCursor(1)} {
+		_, err = ParseTimeCursor(cur)
+		assert.ErrorIs(t, err, ErrInvalidCursor, cur)
+	}
+}
+
+func TestCursors(t *testing.T) {
+	p := New(Default())
+
+	// First page.
+	s := p.New(1, 10)
+	s.SetKeys(1, 10)
+	s.SetHasMore(true)
+	prev, next := s.Cursors()
+	assert.Equal(t, prev, "")
+	assert.NotEqual(t, next, "")
+
+	// Last page.
+	s, err := p.NewFromCursor(next, 10)
+	assert.Nil(t, err)
+	assert.Equal(t, s.After, int64(10))
+	s.SetKeys(11, 15)
+	s.SetHasMore(false)
+	prev, next = s.Cursors()
+	assert.NotEqual(t, prev, "")
+	assert.Equal(t, next, "")
+
+	// Back to the first page.
+	s, err = p.NewFromCursor(prev, 10)
+	assert.Nil(t, err)
+	assert.Equal(t, s.Before, int64(11))
+	s.SetKeys(1, 10)
+	s.SetHasMore(false)
+	prev, next = s.Cursors()
+	assert.Equal(t, prev, "")
+	assert.NotEqual(t, next, "")
+
+	s, err = p.NewFromCursor(next, 10)
+	assert.Nil(t, err)
+	assert.Equal(t, s.After, int64(10))
+}
+
+func TestEmptyCursor(t *testing.T) {
+	p := New(Default())
+
+	s, err := p.NewFromCursor("", 20)
+	assert.Nil(t, err)
+	assert.Equal(t, s.Page, 1)
+	assert.Equal(t, s.Offset, 0)
+	assert.Equal(t, s.Limit, 20)
+	assert.Equal(t, s.Cursor, "")
+	assert.Nil(t, s.After)
+	assert.Nil(t, s.Before)
+}
+
+func TestKeysetWhere(t *testing.T) {
+	p := New(Default())
+	s := p.New(1, 10)
+	cols := []string{"created_at", "id"}
+
+	// No cursor.
+	w, args := s.KeysetWhere(cols)
+	assert.Equal(t, w, "")
+	assert.Nil(t, args)
+
+	// Next.
+	s, err := p.NewFromCursor(s.NextCursor([]KeyValue{{"created_at", "2020-01-02T03:04:05Z"}, {"id", 42}}), 10)
+	assert.Nil(t, err)
+	w, args = s.KeysetWhere(cols)
+	assert.Equal(t, w, "(created_at, id) > (?, ?)")
+	assert.Equal(t, args, []interface{}{"2020-01-02T03:04:05Z", int64(42)})
+
+	// Prev.
+	s, err = p.NewFromCursor(s.PrevCursor([]KeyValue{{"created_at", "2020-01-01T00:00:00Z"}, {"id", 7}}), 10)
+	assert.Nil(t, err)
+	w, args = s.KeysetWhere(cols)
+	assert.Equal(t, w, "(created_at, id) < (?, ?)")
+	assert.Equal(t, args, []interface{}{"2020-01-01T00:00:00Z", int64(7)})
+
+	// Mismatched columns.
+	for _, c := range [][]string{{"id"}, {"id", "created_at"}, {"updated_at", "id"}} {
+		w, args = s.KeysetWhere(c)
+		assert.Equal(t, w, "", c)
+		assert.Nil(t, args, c)
+	}
+
+	// Relay cursors retain the column names.
+	s = p.New(1, 10)
+	s.SetKeys([]KeyValue{{"created_at", "2020-01-01T00:00:00Z"}, {"id", 1}}, []KeyValue{{"created_at", "2020-01-02T00:00:00Z"}, {"id", 10}})
+	end := s.PageInfo().EndCursor
+	s, err = p.NewFromRelayArgs(nil, nil, &end, nil)
+	assert.Nil(t, err)
+	w, args = s.KeysetWhere(cols)
+	assert.Equal(t, w, "(created_at, id) > (?, ?)")
+	assert.Equal(t, args, []interface{}{"2020-01-02T00:00:00Z", int64(10)})
+
+	// Composite keys without column names.
+	s, err = p.NewFromCursor(s.NextCursor([]interface{}{"2020-01-02T03:04:05Z", 42}), 10)
+	assert.Nil(t, err)
+	w, args = s.KeysetWhere(cols)
+	assert.Equal(t, w, "")
+	assert.Nil(t, args)
+
+	// Single column.
+	s, err = p.NewFromCursor(s.NextCursor(5), 10)
+	assert.Nil(t, err)
+	w, args = s.KeysetWhere([]string{"id"})
+	assert.Equal(t, w, "id > ?")
+	assert.Equal(t, args, []interface{}{int64(5)})
+}
+
+func TestCursorZeroIndexed(t *testing.T) {
+	o := Default()
+	o.ZeroIndexed = true
+	p := New(o)
+
+	s := p.New(0, 10)
+	s, err := p.NewFromCursor(s.NextCursor(10), 10)
+	assert.Nil(t, err)
+	assert.Equal(t, s.Page, 0)
+	assert.Equal(t, s.Offset, 0)
+	assert.False(t, s.HasPrev())
+
+	// Relay.
+	first := 10
+	s, err = p.NewFromRelayArgs(&first, nil, nil, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, s.Page, 0)
+	assert.Equal(t, s.Offset, 0)
+	assert.False(t, s.PageInfo().HasPreviousPage)
+
+	// search_after.
+	_, s, err = p.NewFromSearchAfter(s.SearchAfter([]interface{}{1}), 10)
+	assert.Nil(t, err)
+	assert.Equal(t, s.Page, 0)
+	assert.Equal(t, s.Offset, 0)
+
+	// Time cursor.
+	s = p.NewFromTimeCursor(time.Now(), 10)
+	assert.Equal(t, s.Page, 0)
+	assert.Equal(t, s.Offset, 0)
+}
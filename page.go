@@ -0,0 +1,41 @@
+package paginator
+
+import (
+	"encoding/json"
+	"net/url"
+)
+
+// Page represents a page of items along with its pagination Set.
+type Page[T any] struct {
+	Items []T
+	Set   Set
+}
+
+// Wrap returns a Page with the given items and pagination Set.
+func Wrap[T any](s Set, items []T) Page[T] {
+	if items == nil {
+		items = []T{}
+	}
+	return Page[T]{Items: items, Set: s}
+}
+
+// MarshalJSON marshals the items along with the pagination Meta fields,
+// eg: {"items": [...], "page": 1, "per_page": 10, ...}.
+func (p Page[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Items []T `json:"items"`
+		Meta
+	}{p.Items, p.Set.Meta()})
+}
+
+// Slice paginates an in-memory slice with the pagination Set created from the
+// given URL query params. The total is set to the number of items and the
+// returned Page has the items on the current page. If all items are
+// requested (PerPage = 0), all of them are returned.
+func Slice[T any](p *Paginator, q url.Values, items []T) (Page[T], Set) {
+	s := p.NewFromURL(q)
+	s.SetTotal(len(items))
+
+	start, end := s.Range(len(items))
+	return Wrap(s, items[start:end]), s
+}
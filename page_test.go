@@ -0,0 +1,76 @@
+package paginator
+
+import (
+	"encoding/json"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPage(t *testing.T) {
+	type thing struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+	}
+
+	p := New(Default())
+	s := p.New(2, 2)
+	s.SetTotal(5)
+
+	b, err := json.Marshal(Wrap(s, []thing{{3, "c"}, {4, "d"}}))
+	assert.NoError(t, err)
+	assert.JSONEq(t, string(b), `{
+		"items": [{"id": 3, "name": "c"}, {"id": 4, "name": "d"}],
+		"page": 2, "per_page": 2, "total": 5, "total_pages": 3,
+		"has_next": true, "has_prev": true
+	}`)
+
+	// No items.
+	s = p.New(1, 10)
+	s.SetTotal(0)
+	b, err = json.Marshal(Wrap[thing](s, nil))
+	assert.NoError(t, err)
+	assert.JSONEq(t, string(b), `{
+		"items": [],
+		"page": 1, "per_page": 10, "total": 0, "total_pages": 0,
+		"has_next": false, "has_prev": false
+	}`)
+}
+
+func TestSlice(t *testing.T) {
+	items := make([]int, 25)
+	for i := range items {
+		items[i] = i + 1
+	}
+
+	p := New(Default())
+	pg, s := Slice(p, url.Values{"page": []string{"2"}}, items)
+	assert.Equal(t, pg.Items, []int{11, 12, 13, 14, 15, 16, 17, 18, 19, 20})
+	assert.Equal(t, s.Total, 25)
+	assert.Equal(t, s.TotalPages, 3)
+	assert.Equal(t, pg.Set, s)
+
+	// Over-range pages are clamped to the last page.
+	pg, s = Slice(p, url.Values{"page": []string{"10"}}, items)
+	assert.Equal(t, s.Page, 3)
+	assert.Equal(t, pg.Items, []int{21, 22, 23, 24, 25})
+
+	// Over-range pages without clamping are empty.
+	o := Default()
+	o.OffsetOnly = true
+	pg, _ = Slice(New(o), url.Values{"page": []string{"10"}}, items)
+	assert.Equal(t, pg.Items, []int{})
+
+	// Negative offsets from a custom OffsetFunc.
+	o = Default()
+	o.OffsetFunc = func(page, perPage int) int { return (page - 2) * perPage }
+	pg, _ = Slice(New(o), url.Values{"page": []string{"1"}}, items)
+	assert.Equal(t, pg.Items, []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10})
+
+	// All.
+	o = Default()
+	o.AllowAll = true
+	pg, _ = Slice(New(o), url.Values{"per_page": []string{"all"}}, items)
+	assert.Equal(t, pg.Items, items)
+}
@@ -10,12 +10,46 @@ package paginator
 
 import (
 	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"html"
+	"html/template"
+	"io"
 	"math"
+	"net/http"
 	"net/url"
+	"sort"
 	"strconv"
+	"strings"
 )
 
+// ErrInvalidParam is returned by NewFromURLStrict() when a pagination
+// query param has a non-numeric value.
+var ErrInvalidParam = errors.New("invalid pagination param")
+
+// ErrInvalidSet is returned by Set.Validate() when a Set is inconsistent.
+var ErrInvalidSet = errors.New("invalid pagination set")
+
+// Alignments of the current page in the page number series for
+// Opt.WindowAlign.
+const (
+	AlignCenter = "center"
+	AlignLeft   = "left"
+	AlignRight  = "right"
+)
+
+// Modes of encoding the page params in generated URLs for Opt.URLMode.
+const (
+	URLModeQuery    = "query"
+	URLModeFragment = "fragment"
+)
+
+// PageGap is the marker in Set.Pages for a gap between page numbers that is
+// to be printed as an ellipsis with Opt.BoundaryPages.
+const PageGap = -1
+
 // Opt represents paginator options.
 type Opt struct {
 	// DefaultPerPage is the default number of items per page.
@@ -27,6 +61,11 @@ type Opt struct {
 	// AllowAll is set to true, this does not take effect.
 	MaxPerPage int
 
+	// MinPerPage is the minimum number of items per page. Requested values
+	// between 1 and MinPerPage are raised to MinPerPage. It is capped at
+	// MaxPerPage.
+	MinPerPage int
+
 	// NumPageNums is the of number of page numbers to generate when
 	// generating page numbers to be printed (eg: 1, 2 ... 10 ..)
 	NumPageNums int
@@ -40,6 +79,29 @@ type Opt struct {
 	// NewFromURL() will pick up the current page number.
 	PageParam string
 
+	// OffsetParam and LimitParam are the names of the query params (in
+	// url.Values) from which NewFromURLOffset() picks up the offset and limit
+	// values. Default values are `offset` and `limit`.
+	OffsetParam string
+	LimitParam  string
+
+	// SortParam and OrderParam are the names of the query params (in
+	// url.Values) from which NewFromURL() picks up the sort field and the
+	// order (asc or desc). Default values are `sort` and `order`.
+	SortParam  string
+	OrderParam string
+
+	// AllowedSortFields is the list of fields that can be sorted by. Requested
+	// fields not in the list fall back to DefaultSort. If the list is empty,
+	// DefaultSort is always used.
+	AllowedSortFields []string
+
+	// DefaultSort is the sort field used when the requested one is absent or
+	// not allowed. DefaultOrder is the order used when the requested one is
+	// absent or invalid. Default value is `asc`.
+	DefaultSort  string
+	DefaultOrder string
+
 	// If this is set to true, `per_page=all` is allowed and LIMIT is set as 0,
 	// allowing queries to fetch all records in the database (by typically issuing
 	// LIMIT NULL in an SQL query)
@@ -47,9 +109,216 @@ type Opt struct {
 	// batch size can be anything.
 	AllowAll bool
 
+	// PerPageHeader is the optional name of an HTTP request header, eg:
+	// X-Per-Page, from which NewFromRequest() picks up the per_page value
+	// when the per_page query param is absent.
+	PerPageHeader string
+
+	// If this is set to true along with AllowAll, a numeric `per_page=0` in
+	// NewFromURL() fetches all records like AllowAllParam. Otherwise, 0 is
+	// treated as absent and DefaultPerPage applies. AllowAllParam and -1
+	// always mean all with AllowAll.
+	ZeroMeansAll bool
+
 	// Query param value for the `page` query to use in NewFromURL() if AllowAll
 	// is set to true. Default value is `all`.
 	AllowAllParam string
+
+	// If this is set to true, page numbers start at 0 instead of 1 everywhere,
+	// in New(), NewFromURL(), Set.Pages, and generated URLs.
+	ZeroIndexed bool
+
+	// AllowedPerPage is an optional list of allowed per_page values, eg:
+	// 10, 25, 50, 100. If set, requested values are snapped to the nearest
+	// allowed value and MaxPerPage does not take effect.
+	AllowedPerPage []int
+
+	// EllipsisText is the text printed between pinned pages and the page
+	// number series. Default value is `...`.
+	EllipsisText string
+
+	// LinkSeparator is the string printed between the links in HTML().
+	// Default value is a space.
+	LinkSeparator string
+
+	// If this is set to true, HTML() prints the links without a separator
+	// and LinkSeparator is ignored.
+	NoLinkSeparator bool
+
+	// If this is set to true, HTML() prints prev and next page links before
+	// and after the page number series.
+	ShowPrevNext bool
+
+	// URLBuilder optionally builds the page URLs in HTML(), PageURL(), and the
+	// other URL generating methods instead of the page query param, eg:
+	// /things/page/2. It receives the URI, the page number, and a copy of the
+	// extra query params.
+	// URLMode and OmitPageOneParam do not take effect when it is set.
+	URLBuilder func(base string, page int, qp url.Values) string
+
+	// URLMode is how the page params are encoded in generated URLs.
+	// URLModeQuery (default) uses the query string, eg: /things?page=2 and
+	// URLModeFragment uses the fragment, eg: /things#page=2, for single-page
+	// apps.
+	URLMode string
+
+	// If this is set to true, the page param is omitted from the URL of the
+	// first page in PageURL(), HTML(), and the other URL generating methods,
+	// eg: /things instead of /things?page=1, for cleaner canonical URLs.
+	OmitPageOneParam bool
+
+	// If this is set to true, HTML() adds a data-page="N" attribute to every
+	// link and data-current="true" to the current page's link for JS hooks.
+	DataAttributes bool
+
+	// If this is set to true, HTML() wraps the page links in a
+	// <nav aria-label="Pagination"> and marks the current page with
+	// aria-current="page" for accessibility.
+	Accessible bool
+
+	// If this is set to true, a negative perPage passed to New() fetches all
+	// records (PerPage = 0) even if AllowAll is false. Unlike AllowAll, this
+	// does not apply to values coming in from requests, eg: NewFromURL(),
+	// allowing all records only to be fetched programmatically.
+	NegativeMeansAll bool
+
+	// OffsetFunc optionally overrides how Set.Offset is computed from the
+	// page number and the per page value. By default, it is
+	// (page - 1) * perPage.
+	OffsetFunc func(page, perPage int) int
+
+	// If this is set to true, the page number series (Pages, PinFirstPage,
+	// PinLastPage) are included when a Set is marshalled to JSON.
+	ExposePages bool
+
+	// If this is set to true, the page number series (Pages) is generated in
+	// descending order. The rendered output is reversed accordingly, with the
+	// pinned last page and the next link printed first.
+	DescendingPages bool
+
+	// BoundaryPages is the number of pages to always show at either end of
+	// the page number series, eg: 1 2 ... 8 9 10 ... 49 50 for 2. If set,
+	// Pages includes the boundary pages with PageGap markers for the gaps
+	// instead of using PinFirstPage and PinLastPage, and AlwaysShowEnds does
+	// not take effect. 0 disables it.
+	BoundaryPages int
+
+	// CompactThreshold is the number of pages above which the page number
+	// series only has the prev, current, and next pages between the pinned
+	// first and last pages instead of NumPageNums pages. 0 disables it.
+	CompactThreshold int
+
+	// WindowRadius is the number of page numbers to generate on either side
+	// of the current page, eg: 2 for 3 4 [5] 6 7. If set, it overrides
+	// NumPageNums with a window of 2*WindowRadius+1 pages.
+	WindowRadius int
+
+	// WindowAlign is the position of the current page in the page number
+	// series. AlignCenter (default) centers it, AlignLeft puts it at the
+	// start of the series showing the pages ahead, and AlignRight puts it at
+	// the end showing the pages behind. The series is shifted at either end
+	// to remain NumPageNums wide.
+	WindowAlign string
+
+	// If this is set to true, the first and the last pages are always pinned
+	// and printed as dedicated links, even when they would fall within the
+	// page number series. Ellipses are then only printed where there is a gap
+	// between a pinned page and the series.
+	AlwaysShowEnds bool
+
+	// If this is set to true, NewReverseFromURL() starts at the last page
+	// when no page is requested.
+	Reverse bool
+
+	// MaxTotal caps the total used for generating page numbers so that pages
+	// beyond it aren't offered even if the real total is larger. The real
+	// total is retained in Set.RealTotal. 0 means no limit.
+	MaxTotal int
+
+	// MaxPage is the maximum page number that can be requested regardless
+	// of the total. 0 means no limit.
+	MaxPage int
+
+	// PerPageAliases optionally maps named per_page values to numbers, eg:
+	// {"small": 10, "medium": 25}, for NewFromURL(). Unknown non-numeric values
+	// fall back to DefaultPerPage.
+	PerPageAliases map[string]int
+
+	// If this is set to true, when the requested page is beyond the total,
+	// it is retained on SetTotal() and Set.OutOfRange is set instead of the
+	// page being reset to the first or the last page.
+	KeepOutOfRangePage bool
+
+	// If this is set to true, SetTotal() only computes TotalPages and the
+	// page number series and never adjusts Page or Offset.
+	OffsetOnly bool
+
+	// OnParse is an optional hook called at the end of NewFromURL() with the
+	// raw requested values (Page, PerPage, Offset, Limit) and the sanitized
+	// Set.
+	OnParse func(raw, sanitized Set)
+
+	// TotalCache is an optional cache for total counts used by
+	// Set.ResolveCached() to avoid counting on every request.
+	TotalCache TotalCache
+
+	// HTMLTemplate is an optional template that HTML() executes with HTMLData
+	// instead of rendering the built-in markup.
+	HTMLTemplate *template.Template
+
+	// Labels are the strings used in Summary() and prev/next controls.
+	// Empty labels are set to their English defaults.
+	Labels Labels
+
+	// Classes are the CSS class names used in HTML(). Empty class names are
+	// set to their defaults.
+	Classes Classes
+}
+
+// Limiter represents a query builder on which Set.Apply() sets the offset
+// and limit values.
+type Limiter interface {
+	Offset(int)
+	Limit(int)
+}
+
+// TotalCache represents a cache for total counts used by Set.ResolveCached().
+// Expiry (TTL) is left to the implementation.
+type TotalCache interface {
+	Get(key string) (int, bool)
+	Set(key string, total int)
+}
+
+// Classes represents the CSS class names used in HTML().
+type Classes struct {
+	Page          string
+	Selected      string
+	First         string
+	Last          string
+	EllipsisFirst string
+	EllipsisLast  string
+	Prev          string
+	Next          string
+	Disabled      string
+	PerPage       string
+	Jump          string
+}
+
+// Labels represents the text strings used in summaries and rendered
+// pagination controls, allowing them to be localized.
+type Labels struct {
+	// Summary() strings, eg: "Showing 11 to 20 of 134 results",
+	// "Showing all 134 results", "No results".
+	Showing   string
+	To        string
+	Of        string
+	All       string
+	Results   string
+	NoResults string
+
+	// Prev and next page controls.
+	Prev string
+	Next string
 }
 
 // Paginator represents a Paginator instance.
@@ -71,11 +340,118 @@ type Set struct {
 	Offset int `json:"-"`
 	Limit  int `json:"-"`
 
+	// Sanitized sort field and order (asc or desc) picked up by NewFromURL().
+	Sort  string `json:"-"`
+	Order string `json:"-"`
+
+	// Keyset (cursor) pagination values. Cursor is the opaque cursor the Set
+	// was created from with NewFromCursor(). After or Before is set to the
+	// last-seen sort key depending on the cursor's direction and can be used
+	// in a query, eg: WHERE id > $after ORDER BY id LIMIT $limit.
+	// With NewFromTimeCursor(), Before is a time.Time.
+	Cursor string      `json:"cursor,omitempty"`
+	After  interface{} `json:"-"`
+	Before interface{} `json:"-"`
+
+	// HasMore is set with SetHasMore() when the total is unknown to indicate
+	// if there is a next page.
+	HasMore bool `json:"-"`
+
+	// PageCapped is set when the requested page exceeds Opt.MaxPage
+	// and is clamped to it.
+	PageCapped bool `json:"-"`
+
+	// OutOfRange is set when the requested page is beyond the total
+	// and Opt.KeepOutOfRangePage is true.
+	OutOfRange bool `json:"-"`
+
+	// RealTotal is the total passed to SetTotal() before it is capped at
+	// Opt.MaxTotal, in which case Total is the capped value.
+	RealTotal int `json:"-"`
+
+	// RequestedPage and RequestedPerPage are the page and per_page values
+	// originally requested before sanitization. A per_page of "all" is -1.
+	RequestedPage    int `json:"-"`
+	RequestedPerPage int `json:"-"`
+
 	// Fields for rendering page numbers.
 	PinFirstPage bool  `json:"-"`
 	PinLastPage  bool  `json:"-"`
 	Pages        []int `json:"-"`
-	pg           *Paginator
+
+	// Items is the complete page number series to be rendered, including
+	// the pinned pages and the ellipses (gaps), in the order in which they
+	// are to be printed.
+	Items []PageItem `json:"-"`
+
+	pg *Paginator
+
+	// Query param names that override the ones in Opt.
+	pageParam    string
+	perPageParam string
+
+	// Keys of the first and last items on the current page for generating
+	// Relay cursors, and whether the Set paginates backwards (Relay last or a
+	// prev cursor).
+	firstKey interface{}
+	lastKey  interface{}
+	backward bool
+
+	// Column names of a composite cursor key for KeysetWhere().
+	keyCols []string
+
+	// Whether SetTotal() and SetHasMore() have been called.
+	hasTotal bool
+	hasMore  bool
+}
+
+// PageItem represents an item in Set.Items, which is either a page number or
+// an ellipsis for a gap in the series.
+type PageItem struct {
+	Num      int
+	Ellipsis bool
+}
+
+// HTMLData is the data passed to Opt.HTMLTemplate for rendering HTML().
+type HTMLData struct {
+	Page         int
+	TotalPages   int
+	FirstPage    int
+	LastPage     int
+	PinFirstPage bool
+	PinLastPage  bool
+	FirstURL     string
+	LastURL      string
+	Pages        []HTMLPage
+}
+
+// HTMLPage represents a page number link in HTMLData. With
+// Opt.BoundaryPages, gaps in the series are items with Ellipsis set.
+type HTMLPage struct {
+	Num      int
+	URL      string
+	Current  bool
+	Ellipsis bool
+}
+
+// PageLink represents an item in the page number series returned by Links().
+// Ellipsis items have no Page or URL.
+type PageLink struct {
+	Page     int    `json:"page"`
+	URL      string `json:"url"`
+	Current  bool   `json:"current"`
+	Ellipsis bool   `json:"ellipsis"`
+}
+
+// Meta represents compact pagination metadata that can be embedded in
+// API responses.
+type Meta struct {
+	Page       int  `json:"page"`
+	PerPage    int  `json:"per_page"`
+	Total      int  `json:"total"`
+	TotalPages int  `json:"total_pages"`
+	HasNext    bool `json:"has_next"`
+	HasPrev    bool `json:"has_prev"`
 }
 
 // Default returns a paginator.Opt with default values set.
@@ -86,8 +462,47 @@ func Default() Opt {
 		NumPageNums:    10,
 		PageParam:      "page",
 		PerPageParam:   "per_page",
+		OffsetParam:    "offset",
+		LimitParam:     "limit",
+		SortParam:      "sort",
+		OrderParam:     "order",
+		DefaultOrder:   "asc",
 		AllowAll:       false,
 		AllowAllParam:  "all",
+		EllipsisText:   "...",
+		Labels:         DefaultLabels(),
+		Classes:        DefaultClasses(),
+	}
+}
+
+// DefaultLabels returns the default English Labels.
+func DefaultLabels() Labels {
+	return Labels{
+		Showing:   "Showing",
+		To:        "to",
+		Of:        "of",
+		All:       "all",
+		Results:   "results",
+		NoResults: "No results",
+		Prev:      "«",
+		Next:      "»",
+	}
+}
+
+// DefaultClasses returns the default CSS class names.
+func DefaultClasses() Classes {
+	return Classes{
+		Page:          "pg-page",
+		Selected:      "pg-selected",
+		First:         "pg-page-first",
+		Last:          "pg-page-last",
+		EllipsisFirst: "pg-page-ellipsis-first",
+		EllipsisLast:  "pg-page-ellipsis-last",
+		Prev:          "pg-prev",
+		Next:          "pg-next",
+		Disabled:      "pg-disabled",
+		PerPage:       "pg-per-page",
+		Jump:          "pg-jump",
 	}
 }
 
@@ -96,154 +511,1792 @@ func New(o Opt) *Paginator {
 	if o.AllowAllParam == "" {
 		o.AllowAllParam = "all"
 	}
+	if o.EllipsisText == "" {
+		o.EllipsisText = "..."
+	}
+	if o.LinkSeparator == "" {
+		o.LinkSeparator = " "
+	}
+	if o.OffsetParam == "" {
+		o.OffsetParam = "offset"
+	}
+	if o.LimitParam == "" {
+		o.LimitParam = "limit"
+	}
+	if o.SortParam == "" {
+		o.SortParam = "sort"
+	}
+	if o.OrderParam == "" {
+		o.OrderParam = "order"
+	}
+	if o.DefaultOrder == "" {
+		o.DefaultOrder = "asc"
+	}
+	if o.MinPerPage > o.MaxPerPage && !o.AllowAll {
+		o.MinPerPage = o.MaxPerPage
+	}
+
+	// Fill empty labels with defaults.
+	d := DefaultLabels()
+	for _, l := range []struct {
+		val *string
+		def string
+	}{
+		{&o.Labels.Showing, d.Showing},
+		{&o.Labels.To, d.To},
+		{&o.Labels.Of, d.Of},
+		{&o.Labels.All, d.All},
+		{&o.Labels.Results, d.Results},
+		{&o.Labels.NoResults, d.NoResults},
+		{&o.Labels.Prev, d.Prev},
+		{&o.Labels.Next, d.Next},
+	} {
+		if *l.val == "" {
+			*l.val = l.def
+		}
+	}
+
+	// Fill empty class names with defaults.
+	c := DefaultClasses()
+	for _, l := range []struct {
+		val *string
+		def string
+	}{
+		{&o.Classes.Page, c.Page},
+		{&o.Classes.Selected, c.Selected},
+		{&o.Classes.First, c.First},
+		{&o.Classes.Last, c.Last},
+		{&o.Classes.EllipsisFirst, c.EllipsisFirst},
+		{&o.Classes.EllipsisLast, c.EllipsisLast},
+		{&o.Classes.Prev, c.Prev},
+		{&o.Classes.Next, c.Next},
+		{&o.Classes.Disabled, c.Disabled},
+		{&o.Classes.PerPage, c.PerPage},
+		{&o.Classes.Jump, c.Jump},
+	} {
+		if *l.val == "" {
+			*l.val = l.def
+		}
+	}
 
 	return &Paginator{
 		o: o,
 	}
 }
 
-// NewFromURL returns a new pagination Set by .
+// Clone returns a new Paginator with a copy of the current options modified
+// by the given function. The original Paginator is not affected.
+func (p *Paginator) Clone(modify func(*Opt)) *Paginator {
+	o := p.o
+	if p.o.AllowedPerPage != nil {
+		o.AllowedPerPage = append([]int(nil), p.o.AllowedPerPage...)
+	}
+	if p.o.AllowedSortFields != nil {
+		o.AllowedSortFields = append([]string(nil), p.o.AllowedSortFields...)
+	}
+	if p.o.PerPageAliases != nil {
+		o.PerPageAliases = make(map[string]int, len(p.o.PerPageAliases))
+		for k, v := range p.o.PerPageAliases {
+			o.PerPageAliases[k] = v
+		}
+	}
+
+	if modify != nil {
+		modify(&o)
+	}
+	return New(o)
+}
+
+// Namespace returns a new Paginator with the page and per_page param names
+// prefixed with the given prefix, eg: users_page and users_per_page for
+// "users".
+func (p *Paginator) Namespace(prefix string) *Paginator {
+	return p.Clone(func(o *Opt) {
+		o.PageParam = prefix + "_" + o.PageParam
+		o.PerPageParam = prefix + "_" + o.PerPageParam
+	})
+}
+
+// Option represents a functional option that modifies Opt.
+type Option func(*Opt)
+
+// NewWithOptions returns a new Paginator instance with Default() options
+// modified by the given functional options.
+func NewWithOptions(opts ...Option) *Paginator {
+	o := Default()
+	for _, fn := range opts {
+		fn(&o)
+	}
+	return New(o)
+}
+
+// WithDefaultPerPage sets Opt.DefaultPerPage.
+func WithDefaultPerPage(n int) Option {
+	return func(o *Opt) {
+		o.DefaultPerPage = n
+	}
+}
+
+// WithMaxPerPage sets Opt.MaxPerPage.
+func WithMaxPerPage(n int) Option {
+	return func(o *Opt) {
+		o.MaxPerPage = n
+	}
+}
+
+// WithNumPageNums sets Opt.NumPageNums.
+func WithNumPageNums(n int) Option {
+	return func(o *Opt) {
+		o.NumPageNums = n
+	}
+}
+
+// WithPageParam sets Opt.PageParam.
+func WithPageParam(p string) Option {
+	return func(o *Opt) {
+		o.PageParam = p
+	}
+}
+
+// WithPerPageParam sets Opt.PerPageParam.
+func WithPerPageParam(p string) Option {
+	return func(o *Opt) {
+		o.PerPageParam = p
+	}
+}
+
+// WithAllowAll sets Opt.AllowAll.
+func WithAllowAll(b bool) Option {
+	return func(o *Opt) {
+		o.AllowAll = b
+	}
+}
+
+// WithAllowAllParam sets Opt.AllowAllParam.
+func WithAllowAllParam(p string) Option {
+	return func(o *Opt) {
+		o.AllowAllParam = p
+	}
+}
+
+// WithZeroIndexed sets Opt.ZeroIndexed.
+func WithZeroIndexed(b bool) Option {
+	return func(o *Opt) {
+		o.ZeroIndexed = b
+	}
+}
+
+// WithHTMLTemplate sets Opt.HTMLTemplate.
+func WithHTMLTemplate(t *template.Template) Option {
+	return func(o *Opt) {
+		o.HTMLTemplate = t
+	}
+}
+
+// WithLabels sets Opt.Labels.
+func WithLabels(l Labels) Option {
+	return func(o *Opt) {
+		o.Labels = l
+	}
+}
+
+// NewFromURL returns a new pagination Set from URL query params.
 func (p *Paginator) NewFromURL(q url.Values) Set {
+	return p.NewFromURLWithParams(q, p.o.PageParam, p.o.PerPageParam)
+}
+
+// Parse returns a new pagination Set from URL query params like NewFromURL()
+// along with a copy of the params without the page and per_page params, which
+// can be passed to HTML() and the other URL generating methods as extra params.
+func (p *Paginator) Parse(q url.Values) (Set, url.Values) {
+	rest := copyValues(q)
+	rest.Del(p.o.PageParam)
+	rest.Del(p.o.PerPageParam)
+	return p.NewFromURL(q), rest
+}
+
+// NewFromURLStrict returns a new pagination Set from URL query params like
+// NewFromURL(), but returns an error wrapping ErrInvalidParam if the page or
+// per_page params are present but are not numeric (other than the AllowAllParam
+// value or one of PerPageAliases). Absent params are not errors.
+func (p *Paginator) NewFromURLStrict(q url.Values) (Set, error) {
+	if v := q.Get(p.o.PageParam); v != "" {
+		if _, err := strconv.Atoi(v); err != nil {
+			return Set{}, fmt.Errorf("%w: %s", ErrInvalidParam, p.o.PageParam)
+		}
+	}
+
+	if v := q.Get(p.o.PerPageParam); v != "" && v != p.o.AllowAllParam {
+		if _, ok := p.o.PerPageAliases[v]; !ok {
+			if _, err := strconv.Atoi(v); err != nil {
+				return Set{}, fmt.Errorf("%w: %s", ErrInvalidParam, p.o.PerPageParam)
+			}
+		}
+	}
+
+	return p.NewFromURL(q), nil
+}
+
+// NewFromURLWithParams returns a new pagination Set from URL query params
+// using the given page and per_page param names instead of the ones in Opt.
+// The param names are retained on the Set for generating URLs.
+func (p *Paginator) NewFromURLWithParams(q url.Values, pageParam, perPageParam string) Set {
 	var (
-		perPage, _ = strconv.Atoi(q.Get(p.o.PerPageParam))
-		page, _    = strconv.Atoi(q.Get(p.o.PageParam))
+		perPage, _ = strconv.Atoi(q.Get(perPageParam))
+		page, _    = strconv.Atoi(q.Get(pageParam))
 	)
 
-	if q.Get(p.o.PerPageParam) == p.o.AllowAllParam {
+	// Retain the per_page value as requested before aliases and sanitization.
+	reqPerPage := perPage
+	if q.Get(perPageParam) == p.o.AllowAllParam {
+		reqPerPage = -1
+	}
+
+	if v, ok := p.o.PerPageAliases[q.Get(perPageParam)]; ok {
+		perPage = v
+	}
+	if q.Get(perPageParam) == p.o.AllowAllParam || (p.o.ZeroMeansAll && q.Get(perPageParam) == "0") {
 		perPage = -1
 	}
 
-	return p.New(page, perPage)
+	// An absent page param is a request for the first page.
+	if q.Get(pageParam) == "" {
+		page = p.firstPage()
+	}
+
+	s := p.New(page, p.publicPerPage(perPage))
+	s.RequestedPerPage = reqPerPage
+	s.pageParam = pageParam
+	s.perPageParam = perPageParam
+	s.Sort, s.Order = p.sortOrder(q)
+
+	if p.o.OnParse != nil {
+		raw := s
+		raw.Page = s.RequestedPage
+		raw.PerPage = s.RequestedPerPage
+		raw.Offset = p.offset(raw.Page, raw.PerPage)
+		raw.Limit = raw.PerPage
+		p.o.OnParse(raw, s)
+	}
+	return s
 }
 
-// New returns a page Set.
-func (p *Paginator) New(page, perPage int) Set {
-	if perPage < 0 && p.o.AllowAll {
-		perPage = 0
-	} else if perPage < 1 {
-		perPage = p.o.DefaultPerPage
-	} else if !p.o.AllowAll && perPage > p.o.MaxPerPage {
-		perPage = p.o.MaxPerPage
+// sortOrder returns the sanitized sort field and order from URL query params.
+func (p *Paginator) sortOrder(q url.Values) (string, string) {
+	field := p.o.DefaultSort
+	if v := q.Get(p.o.SortParam); v != "" {
+		for _, f := range p.o.AllowedSortFields {
+			if v == f {
+				field = v
+				break
+			}
+		}
 	}
-	if page < 1 {
-		page = 1
+
+	order := p.o.DefaultOrder
+	switch v := strings.ToLower(q.Get(p.o.OrderParam)); v {
+	case "asc", "desc":
+		order = v
 	}
+	return field, order
+}
 
-	return Set{
-		Page:    page,
-		PerPage: perPage,
-		Offset:  (page - 1) * perPage,
-		Limit:   perPage,
-		pg:      p,
+// NewReverseFromURL returns a new pagination Set from URL query params with
+// the total set. If Opt.Reverse is true and no page is requested, the Set
+// starts at the last page instead of the first.
+func (p *Paginator) NewReverseFromURL(q url.Values, total int) Set {
+	s := p.NewFromURL(q)
+	if p.o.Reverse && q.Get(p.o.PageParam) == "" && s.PerPage > 0 && total > 0 {
+		numPages := TotalPages(total, s.PerPage)
+
+		// Retain the values parsed from the URL.
+		r := p.New(numPages-1+p.firstPage(), s.PerPage)
+		r.Sort, r.Order = s.Sort, s.Order
+		r.pageParam, r.perPageParam = s.pageParam, s.perPageParam
+		s = r
 	}
+
+	s.SetTotal(total)
+	return s
 }
 
-// SetTotal sets the total count of results after a Set has been used to fetch
-// results. This is necessary to generate page numbers.
-func (s *Set) SetTotal(t int) {
-	s.Total = t
-	s.generateNumbers()
+// NewFromRequest returns a new pagination Set from an HTTP request's query
+// params. For form-encoded POST requests, the form values are merged with
+// the query params, with the form values taking precedence. If
+// Opt.PerPageHeader is set and the per_page param is absent, the per_page
+// value is picked up from the header.
+func (p *Paginator) NewFromRequest(r *http.Request) Set {
+	q := r.URL.Query()
+	if r.Method == http.MethodPost &&
+		strings.HasPrefix(r.Header.Get("Content-Type"), "application/x-www-form-urlencoded") {
+		if err := r.ParseForm(); err == nil {
+			q = r.Form
+		}
+	}
+
+	if p.o.PerPageHeader != "" && q.Get(p.o.PerPageParam) == "" {
+		if v := r.Header.Get(p.o.PerPageHeader); v != "" {
+			q = copyValues(q)
+			q.Set(p.o.PerPageParam, v)
+		}
+	}
+
+	return p.NewFromURL(q)
 }
 
-// SetParams sets additional query params to be appended to the paginated URLs.
-func (s *Set) SetParams(p url.Values) {
-	s.Params = p
+// NewFromURLOffset returns a new pagination Set from offset and limit URL
+// query params, eg: ?offset=40&limit=20, instead of page numbers.
+func (p *Paginator) NewFromURLOffset(q url.Values) Set {
+	var (
+		offset, _ = strconv.Atoi(q.Get(p.o.OffsetParam))
+		limit, _  = strconv.Atoi(q.Get(p.o.LimitParam))
+	)
+
+	if q.Get(p.o.LimitParam) == p.o.AllowAllParam {
+		limit = -1
+	}
+
+	return p.NewFromOffsetLimit(offset, p.publicPerPage(limit))
 }
 
-// generateNumbers generates page numbers on a Set and fills the .PageFirst,
-// .Pages[], and .PageLast values.
-func (s *Set) generateNumbers() {
-	if s.Total <= s.PerPage {
-		s.Offset = 0
-		s.Page = 1
-		return
+// NewFromOffsetLimit returns a new pagination Set from an offset and a limit
+// instead of page numbers. The page is derived as offset/limit + 1 and the
+// offset is retained as it is even if it is not a multiple of the limit.
+func (p *Paginator) NewFromOffsetLimit(offset, limit int) Set {
+	if offset < 0 {
+		offset = 0
 	}
 
-	numPages := int(math.Ceil(float64(s.Total) / float64(s.PerPage)))
-	s.TotalPages = numPages
-	half := (s.pg.o.NumPageNums / 2)
+	// Sanitize the limit before deriving the page from it.
+	perPage := p.New(p.firstPage(), limit).PerPage
 
-	if s.Page > numPages {
-		s.Offset = (numPages - 1) * s.PerPage
+	page := p.firstPage()
+	if perPage > 0 {
+		page += offset / perPage
+	}
 
-		s.Page = numPages
+	s := p.New(page, limit)
+	if !s.PageCapped {
+		s.Offset = offset
 	}
+	return s
+}
 
-	// First and last page numbers to print, half towards the back
-	// and half towards the front.
+// NewFromMap returns a new pagination Set from a map, eg: a decoded JSON
+// request body. Numeric and string values are accepted for the page and
+// per_page keys.
+func (p *Paginator) NewFromMap(m map[string]interface{}) Set {
 	var (
-		first = s.Page - half
-		last  = s.Page + half
+		perPage, _  = toInt(m[p.o.PerPageParam])
+		page, found = toInt(m[p.o.PageParam])
 	)
-	if first < 1 {
-		first = 1
+
+	if v, ok := m[p.o.PerPageParam].(string); ok && v == p.o.AllowAllParam {
+		perPage = -1
 	}
-	if last > numPages {
-		last = numPages
+
+	// An absent page is a request for the first page.
+	if !found {
+		page = p.firstPage()
+	}
+
+	return p.New(page, p.publicPerPage(perPage))
+}
+
+// EmptySet returns a Set for a query with no results with the first page,
+// the default per page value, a zero total, and an empty page number series.
+func (p *Paginator) EmptySet() Set {
+	s := p.New(p.firstPage(), p.o.DefaultPerPage)
+	s.SetTotal(0)
+	s.Pages = []int{}
+	return s
+}
+
+// publicPerPage sanitizes a per_page value coming in from a request. Negative
+// values (all) are only allowed with AllowAll and not NegativeMeansAll, which
+// only applies to New().
+func (p *Paginator) publicPerPage(perPage int) int {
+	if perPage < 0 && !p.o.AllowAll {
+		return 0
+	}
+	return perPage
+}
+
+// New returns a page Set.
+func (p *Paginator) New(page, perPage int) Set {
+	return p.newSet(page, perPage, p.o.MaxPerPage)
+}
+
+// NewWithMax returns a page Set like New() but with maxPerPage overriding
+// Opt.MaxPerPage for this call only. If maxPerPage is 0, Opt.MaxPerPage
+// applies.
+func (p *Paginator) NewWithMax(page, perPage, maxPerPage int) Set {
+	if maxPerPage < 1 {
+		maxPerPage = p.o.MaxPerPage
 	}
-	if numPages > s.pg.o.NumPageNums {
-		if last < numPages && s.Page <= half {
-			last = first + s.pg.o.NumPageNums - 1
+	return p.newSet(page, perPage, maxPerPage)
+}
+
+// newSet returns a page Set with perPage capped at maxPerPage.
+func (p *Paginator) newSet(page, perPage, maxPerPage int) Set {
+	reqPage, reqPerPage := page, perPage
+	if perPage < 0 && (p.o.AllowAll || p.o.NegativeMeansAll) {
+		perPage = 0
+	} else if perPage < 1 {
+		perPage = p.o.DefaultPerPage
+	} else {
+		if perPage < p.o.MinPerPage {
+			perPage = p.o.MinPerPage
 		}
-		if s.Page > numPages-half {
-			first = last - s.pg.o.NumPageNums
+
+		if len(p.o.AllowedPerPage) > 0 {
+			perPage = nearest(p.o.AllowedPerPage, perPage)
+		} else if !p.o.AllowAll && perPage > maxPerPage {
+			perPage = maxPerPage
 		}
 	}
+	first := p.firstPage()
+	if page < first {
+		page = first
+	}
 
-	// If first in the page number series isn't 1, pin it.
-	if first != 1 {
-		s.PinFirstPage = true
+	capped := false
+	if p.o.MaxPage > 0 && page > p.o.MaxPage {
+		page = p.o.MaxPage
+		capped = true
 	}
 
-	// If last page in the page number series is not the actual last page,
-	// pin it.
-	if last != numPages {
-		s.PinLastPage = true
+	return Set{
+		Page:       page,
+		PerPage:    perPage,
+		Offset:     p.offset(page, perPage),
+		Limit:      perPage,
+		PageCapped: capped,
+		pg:         p,
+
+		RequestedPage:    reqPage,
+		RequestedPerPage: reqPerPage,
 	}
+}
 
-	s.Pages = make([]int, 0, last-first+1)
-	for i := first; i <= last; i++ {
-		s.Pages = append(s.Pages, i)
+// SetTotal sets the total count of results after a Set has been used to fetch
+// results. This is necessary to generate page numbers. It can be called
+// multiple times and the page numbers are recomputed each time.
+func (s *Set) SetTotal(t int) {
+	s.RealTotal = t
+	if s.pg.o.MaxTotal > 0 && t > s.pg.o.MaxTotal {
+		t = s.pg.o.MaxTotal
+	}
+
+	s.Total = t
+	s.hasTotal = true
+	s.TotalPages = 0
+	s.Pages = nil
+	s.PinFirstPage = false
+	s.PinLastPage = false
+	s.OutOfRange = false
+	s.generateNumbers()
+
+	series := s.series()
+	s.Items = make([]PageItem, 0, len(series))
+	for _, it := range series {
+		if it.kind == itemEllipsisFirst || it.kind == itemEllipsisLast {
+			s.Items = append(s.Items, PageItem{Ellipsis: true})
+			continue
+		}
+		s.Items = append(s.Items, PageItem{Num: it.page})
 	}
 }
 
-// HTML prints pagination as HTML. It takes optional query params that
-// are appended to every page URL.
-func (s *Set) HTML(uri string, qp url.Values) string {
-	if qp == nil {
-		qp = url.Values{}
+// Resolve calls countFn to fetch the total count of results and sets it on
+// the Set with SetTotal(). If the context is canceled, the context's error
+// is returned.
+func (s *Set) Resolve(ctx context.Context, countFn func(ctx context.Context) (int, error)) error {
+	if err := ctx.Err(); err != nil {
+		return err
 	}
 
-	var b bytes.Buffer
-	if s.PinFirstPage {
-		qp.Set(s.pg.o.PageParam, "1")
-		u := uri + "?" + qp.Encode()
-		b.WriteString(`<a class="pg-page-first" href="` + u + `">`)
-		b.WriteString("1")
-		b.WriteString(`</a> `)
-		b.WriteString(`<span class="pg-page-ellipsis-first">...</span> `)
+	total, err := countFn(ctx)
+	if err != nil {
+		return err
+	}
+	if err := ctx.Err(); err != nil {
+		return err
 	}
-	for _, p := range s.Pages {
-		c := ""
-		if s.Page == p {
-			c = " pg-selected"
-		}
 
-		qp.Set(s.pg.o.PageParam, fmt.Sprintf("%d", p))
-		u := uri + "?" + qp.Encode()
+	s.SetTotal(total)
+	return nil
+}
 
-		b.WriteString(`<a class="pg-page` + c + `" href="` + u + `">`)
-		b.WriteString(fmt.Sprintf("%d", p))
-		b.WriteString(`</a> `)
+// ResolveCached sets the total on the Set from Opt.TotalCache for the given
+// key if it is cached, and otherwise, calls countFn to fetch it and caches
+// it. If Opt.TotalCache is not set, countFn is always called.
+func (s *Set) ResolveCached(key string, countFn func() (int, error)) error {
+	c := s.pg.o.TotalCache
+	if c != nil {
+		if total, ok := c.Get(key); ok {
+			s.SetTotal(total)
+			return nil
+		}
 	}
-	if s.PinLastPage {
-		qp.Set(s.pg.o.PageParam, fmt.Sprintf("%d", s.TotalPages))
-		u := uri + "?" + qp.Encode()
 
-		b.WriteString(`<span class="pg-page-ellipsis-last">...</span> `)
-		b.WriteString(`<a class="pg-page-last" href="` + u + `">`)
-		b.WriteString(fmt.Sprintf("%d", s.TotalPages))
-		b.WriteString(`</a> `)
+	total, err := countFn()
+	if err != nil {
+		return err
 	}
-	return b.String()
+	if c != nil {
+		c.Set(key, total)
+	}
+
+	s.SetTotal(total)
+	return nil
+}
+
+// Validate checks that the Set is internally consistent and returns an error
+// wrapping ErrInvalidSet if it is not. The offset must be within the page
+// for offset pagination, the page must be within the first and the last pages
+// once the total is set, and the number of pages in Pages must not exceed
+// NumPageNums or the WindowRadius window (plus the BoundaryPages).
+func (s *Set) Validate() error {
+	if s.pg == nil {
+		return fmt.Errorf("%w: no paginator", ErrInvalidSet)
+	}
+
+	// Offsets are not used in cursor pagination. The offset may be anywhere
+	// within the page with NewFromOffsetLimit().
+	if s.After == nil && s.Before == nil {
+		o := s.pg.offset(s.Page, s.PerPage)
+		if s.Offset < o || (s.PerPage > 0 && s.Offset >= o+s.PerPage) || (s.PerPage == 0 && s.Offset != o) {
+			return fmt.Errorf("%w: offset %d is not within page %d (offset %d)", ErrInvalidSet, s.Offset, s.Page, o)
+		}
+	}
+
+	if s.hasTotal && s.TotalPages > 0 && !s.OutOfRange && !s.pg.o.OffsetOnly {
+		if s.Page < s.firstPage() || s.Page > s.lastPage() {
+			return fmt.Errorf("%w: page %d is not within %d and %d", ErrInvalidSet, s.Page, s.firstPage(), s.lastPage())
+		}
+	}
+
+	// The compact series always has the prev, current, and next pages. With
+	// BoundaryPages, single page gaps on either side of the window are filled
+	// with the page.
+	limit := s.pg.numPageNums()
+	if s.pg.o.CompactThreshold > 0 && limit < 3 {
+		limit = 3
+	}
+	if s.pg.o.BoundaryPages > 0 {
+		limit += s.pg.o.BoundaryPages*2 + 2
+	}
+	n := 0
+	for _, p := range s.Pages {
+		if p != PageGap {
+			n++
+		}
+	}
+	if n > limit {
+		return fmt.Errorf("%w: %d page numbers exceed %d", ErrInvalidSet, n, limit)
+	}
+	return nil
+}
+
+// Clamped returns true if the requested page was out of range and was
+// adjusted to the first or the last page.
+func (s *Set) Clamped() bool {
+	return s.Page != s.RequestedPage
+}
+
+// HasPrev returns true if there is a page before the current page.
+func (s *Set) HasPrev() bool {
+	return s.Page > s.firstPage()
+}
+
+// HasNext returns true if there is a page after the current page. If neither
+// SetTotal() nor SetHasMore() has been called, it always returns false as
+// the total number of pages is unknown.
+func (s *Set) HasNext() bool {
+	return s.HasMore || s.Page < s.lastPage()
+}
+
+// IsFirst returns true if the current page is the first page.
+func (s *Set) IsFirst() bool {
+	return s.Page == s.firstPage()
+}
+
+// IsLast returns true if the current page is the last page. It always
+// returns false before SetTotal() is called as the last page is unknown.
+func (s *Set) IsLast() bool {
+	if !s.hasTotal {
+		return false
+	}
+	return s.TotalPages <= 1 || s.Page == s.lastPage()
+}
+
+// LastPageOffset returns the offset of the last page. It returns 0 if there
+// is only one page or if SetTotal() has not been called.
+func (s *Set) LastPageOffset() int {
+	if !s.hasTotal || s.TotalPages <= 1 {
+		return 0
+	}
+	return s.pg.offset(s.lastPage(), s.PerPage)
+}
+
+// Empty returns true if there are no results. It also returns true before
+// SetTotal() is called as the total is unknown.
+func (s *Set) Empty() bool {
+	return s.Total == 0
+}
+
+// CurrentPageCount returns the number of items on the current page, which is
+// PerPage for full pages and the remainder on the last page. It returns 0
+// before SetTotal() is called.
+func (s *Set) CurrentPageCount() int {
+	if !s.hasTotal {
+		return 0
+	}
+
+	n := s.Total - s.Offset
+	if n < 0 {
+		return 0
+	}
+	if s.PerPage > 0 && n > s.PerPage {
+		return s.PerPage
+	}
+	return n
+}
+
+// SetHasMore sets whether there are more results after the current page
+// for when the total is unknown. HasNext() returns true if hasMore is true.
+func (s *Set) SetHasMore(hasMore bool) {
+	s.HasMore = hasMore
+	s.hasMore = true
+}
+
+// PrevPage returns the previous page number. On the first page, it returns
+// the current page number. If the page is beyond the last page (OutOfRange),
+// it returns the last page.
+func (s *Set) PrevPage() int {
+	if !s.HasPrev() {
+		return s.Page
+	}
+	if s.OutOfRange {
+		if last := s.lastPage(); last >= s.firstPage() {
+			return last
+		}
+		return s.firstPage()
+	}
+	return s.Page - 1
+}
+
+// NextPage returns the next page number. On the last page, it returns the
+// current page number. As the total number of pages is unknown until
+// SetTotal() or SetHasMore() is called, it always returns the current page
+// number before that.
+func (s *Set) NextPage() int {
+	if !s.HasNext() {
+		return s.Page
+	}
+	return s.Page + 1
+}
+
+// Window returns the first and the last page numbers in the page number
+// series (Pages). If the series is empty, both are the first page.
+func (s *Set) Window() (int, int) {
+	if len(s.Pages) == 0 {
+		return s.firstPage(), s.firstPage()
+	}
+	return s.Pages[0], s.Pages[len(s.Pages)-1]
+}
+
+// Meta returns the pagination metadata of the Set for API responses.
+func (s *Set) Meta() Meta {
+	return Meta{
+		Page:       s.Page,
+		PerPage:    s.PerPage,
+		Total:      s.Total,
+		TotalPages: s.TotalPages,
+		HasNext:    s.HasNext(),
+		HasPrev:    s.HasPrev(),
+	}
+}
+
+// Summary returns a human readable summary of the range of results on the
+// current page, eg: "Showing 11 to 20 of 134 results". If there are no
+// results on the current page, Labels.NoResults is returned.
+// The strings are picked up from Opt.Labels.
+func (s *Set) Summary() string {
+	l := s.pg.o.Labels
+	if s.Total == 0 || s.Offset >= s.Total {
+		return l.NoResults
+	}
+	if s.PerPage == 0 {
+		return fmt.Sprintf("%s %s %d %s", l.Showing, l.All, s.Total, l.Results)
+	}
+
+	last := s.Offset + s.PerPage
+	if last > s.Total {
+		last = s.Total
+	}
+	return fmt.Sprintf("%s %d %s %d %s %d %s", l.Showing, s.Offset+1, l.To, last, l.Of, s.Total, l.Results)
+}
+
+// SetHeaders sets the X-Page and X-Per-Page HTTP headers, and the
+// X-Total-Count and X-Total-Pages headers if the total is known.
+func (s *Set) SetHeaders(h http.Header) {
+	h.Set("X-Page", strconv.Itoa(s.Page))
+	h.Set("X-Per-Page", strconv.Itoa(s.PerPage))
+
+	if s.Total == 0 && s.TotalPages == 0 {
+		return
+	}
+	h.Set("X-Total-Count", strconv.Itoa(s.Total))
+	h.Set("X-Total-Pages", strconv.Itoa(s.TotalPages))
+}
+
+// SQLLimit returns the limit and offset values that can be passed directly
+// to a parameterized SQL query, eg: LIMIT $1 OFFSET $2. When all records are
+// requested (PerPage = 0 with AllowAll), limit is nil, which translates
+// to LIMIT NULL.
+func (s *Set) SQLLimit() (interface{}, int) {
+	if s.PerPage == 0 {
+		return nil, s.Offset
+	}
+	return s.Limit, s.Offset
+}
+
+// Apply sets the offset and limit values of the Set on a query builder. When
+// all records are requested (PerPage = 0 with AllowAll), the limit is not set.
+func (s *Set) Apply(q Limiter) {
+	q.Offset(s.Offset)
+	if s.PerPage != 0 {
+		q.Limit(s.Limit)
+	}
+}
+
+// Range returns the start and end indices of the items on the current page
+// for slicing an in-memory list of total items, eg: items[start:end]. When
+// all records are requested (PerPage = 0 with AllowAll), it returns 0, total.
+func (s *Set) Range(total int) (int, int) {
+	if s.PerPage == 0 {
+		return 0, total
+	}
+
+	// A custom OffsetFunc may return a negative offset.
+	start := s.Offset
+	if start < 0 {
+		start = 0
+	}
+	if start > total {
+		start = total
+	}
+	end := start + s.PerPage
+	if end > total {
+		end = total
+	}
+	return start, end
+}
+
+// SQLClause returns an SQL clause with the limit and offset values, eg:
+// "LIMIT 10 OFFSET 20", that works with Postgres, MySQL, and SQLite. When
+// all records are requested (PerPage = 0 with AllowAll), it returns an empty
+// string. As the values are sanitized integers, it is safe to concatenate
+// the clause into a query.
+func (s *Set) SQLClause() string {
+	if s.PerPage == 0 {
+		return ""
+	}
+	return "LIMIT " + strconv.Itoa(s.Limit) + " OFFSET " + strconv.Itoa(s.Offset)
+}
+
+// MarshalJSON marshals the Set to JSON. The page number series is included
+// only if Opt.ExposePages is set.
+func (s Set) MarshalJSON() ([]byte, error) {
+	// set has the same fields as Set without its methods to avoid recursion.
+	type set Set
+
+	if s.pg == nil || !s.pg.o.ExposePages {
+		return json.Marshal(set(s))
+	}
+
+	pages := s.Pages
+	if pages == nil {
+		pages = []int{}
+	}
+	return json.Marshal(struct {
+		set
+		Pages        []int `json:"pages"`
+		PinFirstPage bool  `json:"pin_first_page"`
+		PinLastPage  bool  `json:"pin_last_page"`
+	}{set(s), pages, s.PinFirstPage, s.PinLastPage})
+}
+
+// SetParams sets additional query params to be appended to the paginated URLs.
+func (s *Set) SetParams(p url.Values) {
+	s.Params = p
+}
+
+// WithTotal sets the total like SetTotal() and returns the Set for chaining,
+// eg: s.WithTotal(100).WithParams(qp).
+func (s *Set) WithTotal(t int) *Set {
+	s.SetTotal(t)
+	return s
+}
+
+// WithParams sets additional query params like SetParams() and returns the
+// Set for chaining.
+func (s *Set) WithParams(p url.Values) *Set {
+	s.SetParams(p)
+	return s
+}
+
+// mergeParams returns a copy of Params with the keys in qp overriding the
+// ones in Params.
+func (s *Set) mergeParams(qp url.Values) url.Values {
+	out := copyValues(s.Params)
+	for k, v := range qp {
+		out[k] = append([]string(nil), v...)
+	}
+	return out
+}
+
+// generateNumbers generates page numbers on a Set and fills the .PageFirst,
+// .Pages[], and .PageLast values.
+func (s *Set) generateNumbers() {
+	// PerPage = 0 (AllowAll) fetches everything in a single page.
+	if s.Total <= s.PerPage || s.PerPage == 0 {
+		s.TotalPages = 0
+		if s.Total > 0 {
+			s.TotalPages = 1
+		}
+
+		if s.pg.o.KeepOutOfRangePage || s.pg.o.OffsetOnly {
+			s.OutOfRange = s.Page > s.firstPage()
+			return
+		}
+
+		s.Page = s.firstPage()
+		s.Offset = s.pg.offset(s.Page, s.PerPage)
+		return
+	}
+
+	numPages := TotalPages(s.Total, s.PerPage)
+	s.TotalPages = numPages
+	var (
+		numPageNums = s.pg.numPageNums()
+		half        = numPageNums / 2
+	)
+
+	if s.Page > s.lastPage() {
+		if s.pg.o.KeepOutOfRangePage || s.pg.o.OffsetOnly {
+			s.OutOfRange = true
+		} else {
+			s.Page = s.lastPage()
+			s.Offset = s.pg.offset(s.Page, s.PerPage)
+		}
+	}
+
+	// Page numbers are computed as 1-indexed and shifted for ZeroIndexed
+	// when the series is generated.
+	var (
+		base = s.firstPage()
+		page = s.Page - base + 1
+	)
+
+	// First and last page numbers to print, half towards the back and half
+	// towards the front (or as per WindowAlign). The window is always
+	// NumPageNums wide (or numPages if there are fewer pages) and is shifted
+	// instead of being clamped at either end.
+	first := page - half
+	switch s.pg.o.WindowAlign {
+	case AlignLeft:
+		first = page
+	case AlignRight:
+		first = page - numPageNums + 1
+	}
+	if first < 1 {
+		first = 1
+	}
+	last := first + numPageNums - 1
+	if last > numPages {
+		last = numPages
+		first = last - numPageNums + 1
+		if first < 1 {
+			first = 1
+		}
+	}
+
+	// With very large page counts, only print the prev, current, and next
+	// pages between the pinned first and last pages.
+	if s.pg.o.CompactThreshold > 0 && numPages > s.pg.o.CompactThreshold {
+		first, last = page-1, page+1
+		if first < 1 {
+			first = 1
+		}
+		if last > numPages {
+			last = numPages
+		}
+	}
+
+	// If first in the page number series isn't 1, pin it.
+	if first != 1 {
+		s.PinFirstPage = true
+	}
+
+	// If last page in the page number series is not the actual last page,
+	// pin it.
+	if last != numPages {
+		s.PinLastPage = true
+	}
+
+	// Always pin the ends and drop them from the series to avoid duplicates.
+	if s.pg.o.AlwaysShowEnds {
+		s.PinFirstPage = true
+		s.PinLastPage = true
+		if first == 1 {
+			first++
+		}
+		if last == numPages {
+			last--
+		}
+	}
+
+	if n := s.pg.o.BoundaryPages; n > 0 {
+		s.boundaryNumbers(numPages, first, last, n)
+	} else {
+		s.Pages = make([]int, 0, last-first+1)
+		for i := first; i <= last; i++ {
+			s.Pages = append(s.Pages, i-1+base)
+		}
+	}
+	if s.pg.o.DescendingPages {
+		for i, j := 0, len(s.Pages)-1; i < j; i, j = i+1, j-1 {
+			s.Pages[i], s.Pages[j] = s.Pages[j], s.Pages[i]
+		}
+	}
+}
+
+// boundaryNumbers fills Pages with the first n and the last n pages around
+// the window of pages from first to last, with PageGap markers in between.
+// The pages are in the 1-indexed form and the pins are not used.
+func (s *Set) boundaryNumbers(numPages, first, last, n int) {
+	s.PinFirstPage = false
+	s.PinLastPage = false
+	s.Pages = make([]int, 0, last-first+1+n*2+2)
+
+	prev := 0
+	add := func(i int) {
+		// Skip pages already added by an overlapping range.
+		if i <= prev {
+			return
+		}
+		// A gap of a single page is printed as the page itself.
+		if prev > 0 && i == prev+2 {
+			s.Pages = append(s.Pages, prev+s.firstPage())
+		} else if prev > 0 && i > prev+2 {
+			s.Pages = append(s.Pages, PageGap)
+		}
+		s.Pages = append(s.Pages, i-1+s.firstPage())
+		prev = i
+	}
+
+	for i := 1; i <= n && i <= numPages; i++ {
+		add(i)
+	}
+	for i := first; i <= last; i++ {
+		add(i)
+	}
+	for i := numPages - n + 1; i <= numPages; i++ {
+		if i >= 1 {
+			add(i)
+		}
+	}
+}
+
+// Kinds of items in a rendered page number series.
+const (
+	itemPage = iota
+	itemFirst
+	itemLast
+	itemEllipsisFirst
+	itemEllipsisLast
+)
+
+// seriesItem is an item in a rendered page number series.
+type seriesItem struct {
+	page int
+	kind int
+}
+
+// series returns the page number series to be rendered including the pinned
+// pages and ellipses in the order in which they are to be printed.
+func (s *Set) series() []seriesItem {
+	out := make([]seriesItem, 0, len(s.Pages)+4)
+	if s.PinFirstPage {
+		out = append(out, seriesItem{page: s.firstPage(), kind: itemFirst})
+		if s.ellipsisFirst() {
+			out = append(out, seriesItem{kind: itemEllipsisFirst})
+		}
+	}
+	for i := range s.Pages {
+		// Pages is already in descending order with DescendingPages.
+		p := s.Pages[i]
+		if s.pg.o.DescendingPages {
+			p = s.Pages[len(s.Pages)-1-i]
+		}
+
+		// Gaps with BoundaryPages.
+		if p == PageGap {
+			kind := itemEllipsisLast
+			if len(out) > 0 && out[len(out)-1].page < s.Page {
+				kind = itemEllipsisFirst
+			}
+			out = append(out, seriesItem{kind: kind})
+			continue
+		}
+		out = append(out, seriesItem{page: p, kind: itemPage})
+	}
+	if s.PinLastPage {
+		if s.ellipsisLast() {
+			out = append(out, seriesItem{kind: itemEllipsisLast})
+		}
+		out = append(out, seriesItem{page: s.lastPage(), kind: itemLast})
+	}
+
+	if s.pg.o.DescendingPages {
+		for i, j := 0, len(out)-1; i < j; i, j = i+1, j-1 {
+			out[i], out[j] = out[j], out[i]
+		}
+	}
+	return out
+}
+
+// bounds returns the lowest and the highest page numbers in the page number
+// series regardless of its order.
+func (s *Set) bounds() (int, int) {
+	first, last := s.Window()
+	if first > last {
+		return last, first
+	}
+	return first, last
+}
+
+// ellipsisFirst returns true if there is a gap between the pinned first page
+// and the page number series that is to be printed as an ellipsis.
+func (s *Set) ellipsisFirst() bool {
+	if !s.PinFirstPage {
+		return false
+	}
+	if !s.pg.o.AlwaysShowEnds {
+		return true
+	}
+	lo, _ := s.bounds()
+	return len(s.Pages) > 0 && lo > s.firstPage()+1
+}
+
+// ellipsisLast returns true if there is a gap between the page number series
+// and the pinned last page that is to be printed as an ellipsis.
+func (s *Set) ellipsisLast() bool {
+	if !s.PinLastPage {
+		return false
+	}
+	if !s.pg.o.AlwaysShowEnds {
+		return true
+	}
+	_, hi := s.bounds()
+	return len(s.Pages) > 0 && hi < s.lastPage()-1
+}
+
+// pageKey returns the name of the page query param.
+func (s *Set) pageKey() string {
+	if s.pageParam != "" {
+		return s.pageParam
+	}
+	return s.pg.o.PageParam
+}
+
+// perPageKey returns the name of the per_page query param.
+func (s *Set) perPageKey() string {
+	if s.perPageParam != "" {
+		return s.perPageParam
+	}
+	return s.pg.o.PerPageParam
+}
+
+// firstPage returns the number of the first page, 0 for ZeroIndexed and 1
+// otherwise.
+func (s *Set) firstPage() int {
+	if s.pg == nil {
+		return 1
+	}
+	return s.pg.firstPage()
+}
+
+// TotalPages returns the number of pages for the given total and the number
+// of items per page. It returns 0 if total is 0 and 1 if perPage is 0 (all).
+func TotalPages(total, perPage int) int {
+	if total <= 0 {
+		return 0
+	}
+	if perPage <= 0 {
+		return 1
+	}
+	return int(math.Ceil(float64(total) / float64(perPage)))
+}
+
+// nearest returns the value in vals that is nearest to n. On a tie,
+// the smaller value is returned.
+func nearest(vals []int, n int) int {
+	out := vals[0]
+	for _, v := range vals[1:] {
+		d, o := abs(v-n), abs(out-n)
+		if d < o || (d == o && v < out) {
+			out = v
+		}
+	}
+	return out
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// toInt coerces an arbitrary numeric or string value into an int.
+func toInt(v interface{}) (int, bool) {
+	switch n := v.(type) {
+	case int:
+		return n, true
+	case int32:
+		return int(n), true
+	case int64:
+		return int(n), true
+	case float32:
+		return int(n), true
+	case float64:
+		return int(n), true
+	case json.Number:
+		i, err := n.Int64()
+		return int(i), err == nil
+	case string:
+		i, err := strconv.Atoi(n)
+		return i, err == nil
+	}
+	return 0, false
+}
+
+// firstPage returns the number of the first page, 0 for ZeroIndexed and 1
+// otherwise.
+func (p *Paginator) firstPage() int {
+	if p.o.ZeroIndexed {
+		return 0
+	}
+	return 1
+}
+
+// numPageNums returns the number of page numbers in the page number series,
+// which is 2*WindowRadius+1 if WindowRadius is set, or NumPageNums.
+func (p *Paginator) numPageNums() int {
+	if p.o.WindowRadius > 0 {
+		return p.o.WindowRadius*2 + 1
+	}
+	return p.o.NumPageNums
+}
+
+// offset returns the offset for the given page number and per page value.
+func (p *Paginator) offset(page, perPage int) int {
+	if p.o.OffsetFunc != nil {
+		return p.o.OffsetFunc(page, perPage)
+	}
+	return (page - p.firstPage()) * perPage
+}
+
+// lastPage returns the number of the last page. If the total is unknown,
+// it is less than firstPage().
+func (s *Set) lastPage() int {
+	return s.TotalPages - 1 + s.firstPage()
+}
+
+// HTML prints pagination as HTML. It takes optional query params that
+// are appended to every page URL along with the ones set with SetParams().
+// If a key is present in both, the value in qp takes precedence.
+// If Opt.HTMLTemplate is set, it is executed with HTMLData instead of the
+// built-in markup, and an empty string is returned if the template fails
+// to execute.
+func (s *Set) HTML(uri string, qp url.Values) string {
+	var b bytes.Buffer
+	if _, err := s.WriteHTML(&b, uri, qp); err != nil {
+		return ""
+	}
+	return b.String()
+}
+
+// HTMLSafe returns the pagination HTML generated by HTML() as template.HTML
+// so that it is not escaped when rendered in an html/template.
+func (s *Set) HTMLSafe(uri string, qp url.Values) template.HTML {
+	return template.HTML(s.HTML(uri, qp))
+}
+
+// WriteHTML writes the pagination HTML generated by HTML() to the given
+// writer and returns the number of bytes written.
+func (s *Set) WriteHTML(w io.Writer, uri string, qp url.Values) (int, error) {
+	qp = s.mergeParams(qp)
+
+	sep := s.pg.o.LinkSeparator
+	if s.pg.o.NoLinkSeparator {
+		sep = ""
+	}
+	b := &htmlWriter{w: w, sep: sep}
+	if s.pg.o.HTMLTemplate != nil {
+		err := s.pg.o.HTMLTemplate.Execute(b, s.templateData(uri, qp))
+		if err == nil {
+			err = b.err
+		}
+		return b.n, err
+	}
+
+	if s.pg.o.Accessible {
+		b.WriteString(`<nav aria-label="Pagination">`)
+	}
+	cl := s.pg.o.Classes
+	var (
+		prev = func() {
+			s.writePrevNext(b, uri, qp, cl.Prev, s.pg.o.Labels.Prev, "Previous page", s.PrevPage(), s.HasPrev())
+		}
+		next = func() {
+			s.writePrevNext(b, uri, qp, cl.Next, s.pg.o.Labels.Next, "Next page", s.NextPage(), s.HasNext())
+		}
+	)
+	if s.pg.o.DescendingPages {
+		prev, next = next, prev
+	}
+
+	// If the total is unknown and SetHasMore() has been used, only the
+	// current page is printed between prev and next.
+	var (
+		showPrevNext = s.pg.o.ShowPrevNext
+		items        = s.series()
+	)
+	if s.hasMore && s.Total == 0 {
+		showPrevNext = true
+		items = []seriesItem{{page: s.Page, kind: itemPage}}
+	}
+
+	if showPrevNext {
+		prev()
+	}
+	for _, it := range items {
+		switch it.kind {
+		case itemEllipsisFirst:
+			b.WriteItem(`<span class="` + cl.EllipsisFirst + `">` + html.EscapeString(s.pg.o.EllipsisText) + `</span>`)
+			continue
+		case itemEllipsisLast:
+			b.WriteItem(`<span class="` + cl.EllipsisLast + `">` + html.EscapeString(s.pg.o.EllipsisText) + `</span>`)
+			continue
+		}
+
+		class := cl.Page
+		switch it.kind {
+		case itemFirst:
+			class = cl.First
+		case itemLast:
+			class = cl.Last
+		}
+
+		c, attr := s.selectedAttrs(it.page)
+		u := html.EscapeString(s.pageURL(uri, qp, it.page))
+		b.WriteItem(`<a class="` + class + c + `"` + attr + s.dataAttrs(it.page) + ` href="` + u + `">` +
+			strconv.Itoa(it.page) + `</a>`)
+	}
+	if showPrevNext {
+		next()
+	}
+	if s.pg.o.Accessible {
+		b.WriteString(`</nav>`)
+	}
+	return b.n, b.err
+}
+
+// htmlWriter wraps an io.Writer for WriteHTML(), counting the bytes
+// written and retaining the first error, after which writes are skipped.
+// Items written with WriteItem() are separated by sep.
+type htmlWriter struct {
+	w     io.Writer
+	n     int
+	err   error
+	sep   string
+	items int
+}
+
+func (h *htmlWriter) Write(b []byte) (int, error) {
+	if h.err != nil {
+		return 0, h.err
+	}
+
+	n, err := h.w.Write(b)
+	h.n += n
+	h.err = err
+	return n, err
+}
+
+func (h *htmlWriter) WriteString(s string) {
+	if h.err != nil {
+		return
+	}
+
+	n, err := io.WriteString(h.w, s)
+	h.n += n
+	h.err = err
+}
+
+// WriteItem writes an item (link) preceded by the separator if it is not
+// the first item.
+func (h *htmlWriter) WriteItem(s string) {
+	if h.items > 0 {
+		h.WriteString(h.sep)
+	}
+	h.items++
+	h.WriteString(s)
+}
+
+// selectedAttrs returns the additional class and attributes for a page link
+// in HTML() if it is the current page.
+func (s *Set) selectedAttrs(page int) (string, string) {
+	if page != s.Page {
+		return "", ""
+	}
+	if s.pg.o.Accessible {
+		return " " + s.pg.o.Classes.Selected, ` aria-current="page"`
+	}
+	return " " + s.pg.o.Classes.Selected, ""
+}
+
+// writePrevNext writes a prev or next link for HTML(). If the link is not
+// enabled, a disabled <span> is written instead.
+func (s *Set) writePrevNext(b *htmlWriter, uri string, qp url.Values, class, label, ariaLabel string, page int, enabled bool) {
+	attr := ""
+	if s.pg.o.Accessible {
+		attr = ` aria-label="` + ariaLabel + `"`
+	}
+
+	if !enabled {
+		b.WriteItem(`<span class="` + class + ` ` + s.pg.o.Classes.Disabled + `"` + attr + `>` + html.EscapeString(label) + `</span>`)
+		return
+	}
+
+	u := html.EscapeString(s.pageURL(uri, qp, page))
+	b.WriteItem(`<a class="` + class + `"` + attr + s.dataAttrs(page) + ` href="` + u + `">` + html.EscapeString(label) + `</a>`)
+}
+
+// dataAttrs returns the data-page (and data-current for the current page)
+// attributes for a link in HTML() if Opt.DataAttributes is set.
+func (s *Set) dataAttrs(page int) string {
+	if !s.pg.o.DataAttributes {
+		return ""
+	}
+
+	out := ` data-page="` + strconv.Itoa(page) + `"`
+	if page == s.Page {
+		out += ` data-current="true"`
+	}
+	return out
+}
+
+// HTMLBootstrap prints pagination as Bootstrap 5 markup with prev and next
+// arrows. It takes optional query params that are appended to every page URL.
+func (s *Set) HTMLBootstrap(uri string, qp url.Values) string {
+	if qp == nil {
+		qp = url.Values{}
+	}
+
+	var (
+		prev = html.EscapeString(s.pg.o.Labels.Prev)
+		next = html.EscapeString(s.pg.o.Labels.Next)
+	)
+
+	var b bytes.Buffer
+	arrow := func(label string, page int, enabled bool) {
+		if enabled {
+			b.WriteString(`<li class="page-item"><a class="page-link" href="` + s.pageURL(uri, qp, page) + `">` + label + `</a></li>`)
+			return
+		}
+		b.WriteString(`<li class="page-item disabled"><span class="page-link">` + label + `</span></li>`)
+	}
+
+	b.WriteString(`<ul class="pagination">`)
+
+	// Prev.
+	if s.pg.o.DescendingPages {
+		arrow(next, s.NextPage(), s.HasNext())
+	} else {
+		arrow(prev, s.PrevPage(), s.HasPrev())
+	}
+
+	for _, it := range s.series() {
+		switch {
+		case it.kind == itemEllipsisFirst || it.kind == itemEllipsisLast:
+			b.WriteString(`<li class="page-item disabled"><span class="page-link">` + html.EscapeString(s.pg.o.EllipsisText) + `</span></li>`)
+		case it.page == s.Page:
+			b.WriteString(`<li class="page-item active" aria-current="page"><a class="page-link" href="` + s.pageURL(uri, qp, it.page) + `">` + strconv.Itoa(it.page) + `</a></li>`)
+		default:
+			b.WriteString(`<li class="page-item"><a class="page-link" href="` + s.pageURL(uri, qp, it.page) + `">` + strconv.Itoa(it.page) + `</a></li>`)
+		}
+	}
+
+	// Next.
+	if s.pg.o.DescendingPages {
+		arrow(prev, s.PrevPage(), s.HasPrev())
+	} else {
+		arrow(next, s.NextPage(), s.HasNext())
+	}
+
+	b.WriteString(`</ul>`)
+	return b.String()
+}
+
+// Text prints pagination as plain text, eg: 1 ... 4 [5] 6 ... 20, with the
+// current page in brackets.
+func (s *Set) Text() string {
+	items := s.series()
+	out := make([]string, 0, len(items))
+	for _, it := range items {
+		switch {
+		case it.kind == itemEllipsisFirst || it.kind == itemEllipsisLast:
+			out = append(out, s.pg.o.EllipsisText)
+		case it.page == s.Page:
+			out = append(out, "["+strconv.Itoa(it.page)+"]")
+		default:
+			out = append(out, strconv.Itoa(it.page))
+		}
+	}
+	return strings.Join(out, " ")
+}
+
+// Markdown prints pagination as Markdown links, eg:
+// [1](/?page=1) ... [4](/?page=4) **5** [6](/?page=6) ... [20](/?page=20),
+// with the current page in bold. It takes optional query params that are
+// appended to every page URL.
+func (s *Set) Markdown(uri string, qp url.Values) string {
+	if qp == nil {
+		qp = url.Values{}
+	}
+
+	items := s.series()
+	out := make([]string, 0, len(items))
+	for _, it := range items {
+		switch {
+		case it.kind == itemEllipsisFirst || it.kind == itemEllipsisLast:
+			out = append(out, s.pg.o.EllipsisText)
+		case it.page == s.Page:
+			out = append(out, "**"+strconv.Itoa(it.page)+"**")
+		default:
+			out = append(out, "["+strconv.Itoa(it.page)+"]("+s.pageURL(uri, qp, it.page)+")")
+		}
+	}
+	return strings.Join(out, " ")
+}
+
+// Links returns the page number series including the pinned pages and the
+// ellipses as structured data for frontends that render their own pagination.
+// It takes optional query params that are appended to every page URL.
+func (s *Set) Links(uri string, qp url.Values) []PageLink {
+	if qp == nil {
+		qp = url.Values{}
+	}
+
+	items := s.series()
+	out := make([]PageLink, 0, len(items))
+	for _, it := range items {
+		if it.kind == itemEllipsisFirst || it.kind == itemEllipsisLast {
+			out = append(out, PageLink{Ellipsis: true})
+			continue
+		}
+		out = append(out, PageLink{
+			Page:    it.page,
+			URL:     s.pageURL(uri, qp, it.page),
+			Current: it.page == s.Page,
+		})
+	}
+	return out
+}
+
+// PerPageSelectHTML prints a <select> dropdown for choosing the number of
+// items per page from the given options, with the current PerPage selected.
+// Selecting an option navigates to the first page with the new per_page value.
+// It takes optional query params that are appended to every URL.
+func (s *Set) PerPageSelectHTML(uri string, qp url.Values, options []int) string {
+	// Work on a copy to not leak the per_page param to the caller's qp.
+	qp = copyValues(qp)
+
+	var b bytes.Buffer
+	b.WriteString(`<select class="` + s.pg.o.Classes.PerPage + `" name="` + html.EscapeString(s.perPageKey()) + `"` +
+		` onchange="window.location.href=this.options[this.selectedIndex].dataset.url">`)
+	for _, n := range options {
+		qp.Set(s.perPageKey(), strconv.Itoa(n))
+		u := s.buildURL(uri, qp, s.firstPage())
+
+		sel := ""
+		if n == s.PerPage {
+			sel = " selected"
+		}
+		b.WriteString(`<option value="` + strconv.Itoa(n) + `" data-url="` + html.EscapeString(u) + `"` + sel + `>`)
+		b.WriteString(strconv.Itoa(n))
+		b.WriteString(`</option>`)
+	}
+	b.WriteString(`</select>`)
+	return b.String()
+}
+
+// JumpFormHTML prints a <form> with a numeric page input bounded by the first
+// and the last pages (if the total is known) for jumping to a page. It takes
+// optional query params that are preserved as hidden inputs along with a
+// non-default per_page value.
+func (s *Set) JumpFormHTML(uri string, qp url.Values) string {
+	qp = copyValues(qp)
+	s.setPerPageParam(qp)
+
+	keys := make([]string, 0, len(qp))
+	for k := range qp {
+		if k != s.pageKey() {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	var b bytes.Buffer
+	b.WriteString(`<form class="` + s.pg.o.Classes.Jump + `" method="get" action="` + html.EscapeString(uri) + `">`)
+	b.WriteString(`<input type="number" name="` + html.EscapeString(s.pageKey()) + `"` +
+		` min="` + strconv.Itoa(s.firstPage()) + `"`)
+
+	// The last page is unknown without the total.
+	if s.TotalPages > 0 {
+		b.WriteString(` max="` + strconv.Itoa(s.lastPage()) + `"`)
+	}
+	b.WriteString(` value="` + strconv.Itoa(s.Page) + `">`)
+	for _, k := range keys {
+		for _, v := range qp[k] {
+			b.WriteString(`<input type="hidden" name="` + html.EscapeString(k) + `" value="` + html.EscapeString(v) + `">`)
+		}
+	}
+	b.WriteString(`</form>`)
+	return b.String()
+}
+
+// templateData returns the data for rendering Opt.HTMLTemplate.
+func (s *Set) templateData(uri string, qp url.Values) HTMLData {
+	d := HTMLData{
+		Page:         s.Page,
+		TotalPages:   s.TotalPages,
+		FirstPage:    s.firstPage(),
+		LastPage:     s.lastPage(),
+		PinFirstPage: s.PinFirstPage,
+		PinLastPage:  s.PinLastPage,
+		FirstURL:     s.pageURL(uri, qp, s.firstPage()),
+		LastURL:      s.pageURL(uri, qp, s.lastPage()),
+		Pages:        make([]HTMLPage, 0, len(s.Pages)),
+	}
+	for _, p := range s.Pages {
+		if p == PageGap {
+			d.Pages = append(d.Pages, HTMLPage{Ellipsis: true})
+			continue
+		}
+		d.Pages = append(d.Pages, HTMLPage{
+			Num:     p,
+			URL:     s.pageURL(uri, qp, p),
+			Current: p == s.Page,
+		})
+	}
+	return d
+}
+
+// LinkHeader returns the value for an RFC 5988 HTTP Link header with the
+// first, prev, next, and last page URLs. prev is omitted on the first page
+// and next and last are omitted on the last page or if the total is unknown.
+// If the total is unknown and SetHasMore(true) has been called, next is
+// included without last.
+// It takes optional query params that are appended to every page URL.
+func (s *Set) LinkHeader(uri string, qp url.Values) string {
+	if qp == nil {
+		qp = url.Values{}
+	}
+
+	links := []string{
+		`<` + s.pageURL(uri, qp, s.firstPage()) + `>; rel="first"`,
+	}
+	if s.HasPrev() {
+		links = append(links, `<`+s.pageURL(uri, qp, s.PrevPage())+`>; rel="prev"`)
+	}
+	if s.HasNext() {
+		links = append(links, `<`+s.pageURL(uri, qp, s.NextPage())+`>; rel="next"`)
+
+		// The last page is unknown with SetHasMore().
+		if s.TotalPages > 0 {
+			links = append(links, `<`+s.pageURL(uri, qp, s.lastPage())+`>; rel="last"`)
+		}
+	}
+	return strings.Join(links, ", ")
+}
+
+// JSONAPILinks returns a JSON:API pagination links object with the self,
+// first, last, prev, and next page URLs. Absent relations, prev on the first
+// page, next on the last page, and last if the total is unknown, are nil,
+// which are marshalled as JSON null.
+// It takes optional query params that are appended to every page URL.
+func (s *Set) JSONAPILinks(uri string, qp url.Values) map[string]*string {
+	link := func(page int) *string {
+		u := s.PageURL(uri, page, qp)
+		return &u
+	}
+
+	out := map[string]*string{
+		"self":  link(s.Page),
+		"first": link(s.firstPage()),
+		"last":  nil,
+		"prev":  nil,
+		"next":  nil,
+	}
+	if s.TotalPages > 0 {
+		out["last"] = link(s.lastPage())
+	}
+	if s.HasPrev() {
+		out["prev"] = link(s.PrevPage())
+	}
+	if s.HasNext() {
+		out["next"] = link(s.NextPage())
+	}
+	return out
+}
+
+// LoadMoreURL returns the URL of the next page for a "load more" button and
+// true if there are more results, based on the total or SetHasMore().
+// On the last page, it returns an empty string and false.
+// It takes optional query params that are appended to the URL.
+func (s *Set) LoadMoreURL(uri string, qp url.Values) (string, bool) {
+	if !s.HasNext() {
+		return "", false
+	}
+	return s.pageURL(uri, qp, s.NextPage()), true
+}
+
+// SEOLinks prints <link> tags for the HTML <head> with the canonical URL of
+// the current page and the prev and next page URLs. prev is omitted on the
+// first page and next on the last page. It takes optional query params that
+// are appended to every page URL.
+func (s *Set) SEOLinks(uri string, qp url.Values) string {
+	if qp == nil {
+		qp = url.Values{}
+	}
+
+	links := []string{
+		`<link rel="canonical" href="` + s.pageURL(uri, qp, s.Page) + `">`,
+	}
+	if s.HasPrev() {
+		links = append(links, `<link rel="prev" href="`+s.pageURL(uri, qp, s.PrevPage())+`">`)
+	}
+	if s.HasNext() {
+		links = append(links, `<link rel="next" href="`+s.pageURL(uri, qp, s.NextPage())+`">`)
+	}
+	return strings.Join(links, "\n")
+}
+
+// PageURL returns the URL for the given page number with optional query params.
+// The page number is clamped between the first and the last page.
+func (s *Set) PageURL(uri string, page int, qp url.Values) string {
+	if qp == nil {
+		qp = url.Values{}
+	}
+
+	if s.TotalPages > 0 && page > s.lastPage() {
+		page = s.lastPage()
+	}
+	if page < s.firstPage() {
+		page = s.firstPage()
+	}
+	return s.pageURL(uri, qp, page)
+}
+
+// QueryString returns the encoded query string with the sanitized page and
+// per_page values of the current page (AllowAllParam for all) along with the
+// optional query params, without a URI, eg: page=2&per_page=20&q=search.
+func (s *Set) QueryString(qp url.Values) string {
+	q := copyValues(qp)
+	q.Set(s.pageKey(), strconv.Itoa(s.Page))
+	if s.PerPage == 0 {
+		q.Set(s.perPageKey(), s.pg.o.AllowAllParam)
+	} else {
+		q.Set(s.perPageKey(), strconv.Itoa(s.PerPage))
+	}
+	return q.Encode()
+}
+
+// AllPageURLs returns the URLs of all the pages from the first to the last
+// page. An optional limit caps the number of URLs returned.
+// It takes optional query params that are appended to every page URL.
+func (s *Set) AllPageURLs(uri string, qp url.Values, limit ...int) []string {
+	n := s.TotalPages
+	if len(limit) > 0 && limit[0] > 0 && limit[0] < n {
+		n = limit[0]
+	}
+
+	out := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		out = append(out, s.pageURL(uri, qp, s.firstPage()+i))
+	}
+	return out
+}
+
+// pageURL returns the URL for the page with the page number param set on a
+// copy of the given query params. The caller's qp is never modified.
+// If PerPage is not the default or the per_page param is in qp, it is set to
+// the sanitized PerPage value (or AllowAllParam for all) so that the value
+// is carried over and a clamped value isn't.
+func (s *Set) pageURL(uri string, qp url.Values, page int) string {
+	q := copyValues(qp)
+	s.setPerPageParam(q)
+	return s.buildURL(uri, q, page)
+}
+
+// setPerPageParam sets the Set's per_page value on the given query params
+// if the key is already present or if the value is not the default.
+func (s *Set) setPerPageParam(q url.Values) {
+	if _, ok := q[s.perPageKey()]; !ok && s.PerPage == s.pg.o.DefaultPerPage {
+		return
+	}
+	if s.PerPage == 0 {
+		q.Set(s.perPageKey(), s.pg.o.AllowAllParam)
+	} else {
+		q.Set(s.perPageKey(), strconv.Itoa(s.PerPage))
+	}
+}
+
+// buildURL returns the URL for the page with the page number param set on
+// the given query params, which are modified. If Opt.URLBuilder is set, the
+// URL is built by it instead.
+func (s *Set) buildURL(uri string, q url.Values, page int) string {
+	if s.pg.o.URLBuilder != nil {
+		return s.pg.o.URLBuilder(uri, page, q)
+	}
+
+	if s.pg.o.OmitPageOneParam && page == s.firstPage() {
+		q.Del(s.pageKey())
+		if len(q) == 0 {
+			return uri
+		}
+	} else {
+		q.Set(s.pageKey(), strconv.Itoa(page))
+	}
+
+	if s.pg.o.URLMode == URLModeFragment {
+		return uri + "#" + q.Encode()
+	}
+	return uri + "?" + q.Encode()
+}
+
+// copyValues returns a deep copy of the given url.Values.
+func copyValues(v url.Values) url.Values {
+	out := make(url.Values, len(v)+1)
+	for k, vals := range v {
+		out[k] = append([]string(nil), vals...)
+	}
+	return out
 }
@@ -1,8 +1,17 @@
 package paginator
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"html/template"
+	"net/http"
+	"net/http/httptest"
 	"net/url"
+	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -81,3 +90,2551 @@ func TestPaginator(t *testing.T) {
 	assert.Equal(t, s.Page, 1)
 	assert.Equal(t, s.PerPage, 0)
 }
+
+func TestHasPrevNext(t *testing.T) {
+	opt := Default()
+	p := New(opt)
+
+	// Total not set.
+	s := p.New(1, 10)
+	assert.False(t, s.HasPrev())
+	assert.False(t, s.HasNext())
+
+	// Single page.
+	s.SetTotal(5)
+	assert.False(t, s.HasPrev())
+	assert.False(t, s.HasNext())
+
+	// First page.
+	s = p.New(1, 10)
+	s.SetTotal(100)
+	assert.False(t, s.HasPrev())
+	assert.True(t, s.HasNext())
+
+	// Middle page.
+	s = p.New(5, 10)
+	s.SetTotal(100)
+	assert.True(t, s.HasPrev())
+	assert.True(t, s.HasNext())
+
+	// Last page.
+	s = p.New(10, 10)
+	s.SetTotal(100)
+	assert.True(t, s.HasPrev())
+	assert.False(t, s.HasNext())
+
+	// AllowAll with PerPage = 0.
+	opt.AllowAll = true
+	p = New(opt)
+	s = p.New(1, -1)
+	s.SetTotal(100)
+	assert.Equal(t, s.PerPage, 0)
+	assert.False(t, s.HasPrev())
+	assert.False(t, s.HasNext())
+}
+
+func TestPrevNextPage(t *testing.T) {
+	p := New(Default())
+
+	// Total not set.
+	s := p.New(3, 10)
+	assert.Equal(t, s.PrevPage(), 2)
+	assert.Equal(t, s.NextPage(), 3)
+
+	// First page.
+	s = p.New(1, 10)
+	s.SetTotal(100)
+	assert.Equal(t, s.PrevPage(), 1)
+	assert.Equal(t, s.NextPage(), 2)
+
+	// Middle page.
+	s = p.New(5, 10)
+	s.SetTotal(100)
+	assert.Equal(t, s.PrevPage(), 4)
+	assert.Equal(t, s.NextPage(), 6)
+
+	// Last page.
+	s = p.New(10, 10)
+	s.SetTotal(100)
+	assert.Equal(t, s.PrevPage(), 9)
+	assert.Equal(t, s.NextPage(), 10)
+
+	// Beyond the last page is snapped to the last page.
+	s = p.New(50, 10)
+	s.SetTotal(100)
+	assert.Equal(t, s.PrevPage(), 9)
+	assert.Equal(t, s.NextPage(), 10)
+}
+
+func TestLinkHeader(t *testing.T) {
+	p := New(Default())
+
+	// Middle page.
+	s := p.New(5, 10)
+	s.SetTotal(100)
+	assert.Equal(t, s.LinkHeader("/things", url.Values{"q": []string{"x"}}),
+		`</things?page=1&q=x>; rel="first", `+
+			`</things?page=4&q=x>; rel="prev", `+
+			`</things?page=6&q=x>; rel="next", `+
+			`</things?page=10&q=x>; rel="last"`)
+
+	// First page.
+	s = p.New(1, 10)
+	s.SetTotal(100)
+	assert.Equal(t, s.LinkHeader("/things", nil),
+		`</things?page=1>; rel="first", `+
+			`</things?page=2>; rel="next", `+
+			`</things?page=10>; rel="last"`)
+
+	// Last page.
+	s = p.New(10, 10)
+	s.SetTotal(100)
+	assert.Equal(t, s.LinkHeader("/things", nil),
+		`</things?page=1>; rel="first", `+
+			`</things?page=9>; rel="prev"`)
+
+	// Unknown total.
+	s = p.New(1, 10)
+	assert.Equal(t, s.LinkHeader("/things", nil), `</things?page=1>; rel="first"`)
+}
+
+func TestSQLLimit(t *testing.T) {
+	opt := Default()
+	p := New(opt)
+
+	s := p.New(3, 10)
+	limit, offset := s.SQLLimit()
+	assert.Equal(t, limit, 10)
+	assert.Equal(t, offset, 20)
+
+	opt.AllowAll = true
+	p = New(opt)
+	s = p.New(1, -1)
+	limit, offset = s.SQLLimit()
+	assert.Nil(t, limit)
+	assert.Equal(t, offset, 0)
+}
+
+func TestMeta(t *testing.T) {
+	p := New(Default())
+	s := p.New(2, 10)
+	s.SetTotal(35)
+	s.SetParams(url.Values{"q": []string{"x"}})
+
+	m := s.Meta()
+	assert.Equal(t, m, Meta{Page: 2, PerPage: 10, Total: 35, TotalPages: 4, HasNext: true, HasPrev: true})
+	assert.Equal(t, s.Meta(), m)
+
+	b, err := json.Marshal(m)
+	assert.NoError(t, err)
+	assert.JSONEq(t, string(b), `{"page": 2, "per_page": 10, "total": 35, "total_pages": 4, "has_next": true, "has_prev": true}`)
+}
+
+func TestHTMLTemplate(t *testing.T) {
+	opt := Default()
+	opt.NumPageNums = 3
+	opt.HTMLTemplate = template.Must(template.New("pg").Parse(
+		`<ul class="pagination">` +
+			`{{ if .PinFirstPage }}<li class="page-item"><a class="page-link" href="{{ .FirstURL }}">{{ .FirstPage }}</a></li>{{ end }}` +
+			`{{ range .Pages }}<li class="page-item{{ if .Current }} active{{ end }}"><a class="page-link" href="{{ .URL }}">{{ .Num }}</a></li>{{ end }}` +
+			`{{ if .PinLastPage }}<li class="page-item"><a class="page-link" href="{{ .LastURL }}">{{ .LastPage }}</a></li>{{ end }}` +
+			`</ul>`))
+	p := New(opt)
+
+	s := p.New(5, 10)
+	s.SetTotal(100)
+	assert.Equal(t, s.HTML("/things", url.Values{"q": []string{"x"}}),
+		`<ul class="pagination">`+
+			`<li class="page-item"><a class="page-link" href="/things?page=1&amp;q=x">1</a></li>`+
+			`<li class="page-item"><a class="page-link" href="/things?page=4&amp;q=x">4</a></li>`+
+			`<li class="page-item active"><a class="page-link" href="/things?page=5&amp;q=x">5</a></li>`+
+			`<li class="page-item"><a class="page-link" href="/things?page=6&amp;q=x">6</a></li>`+
+			`<li class="page-item"><a class="page-link" href="/things?page=10&amp;q=x">10</a></li>`+
+			`</ul>`)
+}
+
+func TestHTMLBootstrap(t *testing.T) {
+	opt := Default()
+	opt.NumPageNums = 3
+	p := New(opt)
+
+	// First page.
+	s := p.New(1, 10)
+	s.SetTotal(100)
+	assert.Equal(t, s.HTMLBootstrap("/things", nil),
+		`<ul class="pagination">`+
+			`<li class="page-item disabled"><span class="page-link">«</span></li>`+
+			`<li class="page-item active" aria-current="page"><a class="page-link" href="/things?page=1">1</a></li>`+
+			`<li class="page-item"><a class="page-link" href="/things?page=2">2</a></li>`+
+			`<li class="page-item"><a class="page-link" href="/things?page=3">3</a></li>`+
+			`<li class="page-item disabled"><span class="page-link">...</span></li>`+
+			`<li class="page-item"><a class="page-link" href="/things?page=10">10</a></li>`+
+			`<li class="page-item"><a class="page-link" href="/things?page=2">»</a></li>`+
+			`</ul>`)
+
+	// Middle page.
+	s = p.New(5, 10)
+	s.SetTotal(100)
+	out := s.HTMLBootstrap("/things", nil)
+	assert.Contains(t, out, `<li class="page-item active" aria-current="page"><a class="page-link" href="/things?page=5">5</a></li>`)
+	assert.Equal(t, strings.Count(out, "active"), 1)
+	assert.Contains(t, out, `<a class="page-link" href="/things?page=4">«</a>`)
+	assert.Contains(t, out, `<a class="page-link" href="/things?page=6">»</a>`)
+
+	// Last page.
+	s = p.New(10, 10)
+	s.SetTotal(100)
+	assert.Contains(t, s.HTMLBootstrap("/things", nil), `<li class="page-item disabled"><span class="page-link">»</span></li></ul>`)
+}
+
+func TestSummary(t *testing.T) {
+	opt := Default()
+	p := New(opt)
+
+	s := p.New(2, 10)
+	s.SetTotal(134)
+	assert.Equal(t, s.Summary(), "Showing 11 to 20 of 134 results")
+
+	// Last page.
+	s = p.New(14, 10)
+	s.SetTotal(134)
+	assert.Equal(t, s.Summary(), "Showing 131 to 134 of 134 results")
+
+	// No results.
+	s = p.New(1, 10)
+	s.SetTotal(0)
+	assert.Equal(t, s.Summary(), "No results")
+
+	// All results.
+	opt.AllowAll = true
+	p = New(opt)
+	s = p.New(1, -1)
+	s.SetTotal(134)
+	assert.Equal(t, s.Summary(), "Showing all 134 results")
+}
+
+func TestLabels(t *testing.T) {
+	opt := Default()
+	opt.Labels = Labels{
+		Showing:   "Affichage",
+		To:        "à",
+		Of:        "sur",
+		All:       "tous les",
+		Results:   "résultats",
+		NoResults: "Aucun résultat",
+		Prev:      "Précédent",
+		Next:      "Suivant",
+	}
+	p := New(opt)
+
+	s := p.New(2, 10)
+	s.SetTotal(134)
+	assert.Equal(t, s.Summary(), "Affichage 11 à 20 sur 134 résultats")
+
+	out := s.HTMLBootstrap("/things", nil)
+	assert.Contains(t, out, `<a class="page-link" href="/things?page=1">Précédent</a>`)
+	assert.Contains(t, out, `<a class="page-link" href="/things?page=3">Suivant</a>`)
+
+	s.SetTotal(0)
+	assert.Equal(t, s.Summary(), "Aucun résultat")
+
+	// Empty labels fall back to the defaults.
+	p = New(Opt{DefaultPerPage: 10, MaxPerPage: 50, NumPageNums: 10, PageParam: "page", PerPageParam: "per_page"})
+	s = p.New(1, 10)
+	s.SetTotal(5)
+	assert.Equal(t, s.Summary(), "Showing 1 to 5 of 5 results")
+}
+
+func TestPageURL(t *testing.T) {
+	p := New(Default())
+	s := p.New(1, 10)
+	s.SetTotal(100)
+
+	assert.Equal(t, s.PageURL("/things", 3, nil), "/things?page=3")
+	assert.Equal(t, s.PageURL("/things", 3, url.Values{"q": []string{"x"}}), "/things?page=3&q=x")
+	assert.Equal(t, s.PageURL("/things", 0, nil), "/things?page=1")
+	assert.Equal(t, s.PageURL("/things", 500, nil), "/things?page=10")
+}
+
+func TestZeroIndexed(t *testing.T) {
+	var (
+		opt = Default()
+		p1  = New(opt)
+	)
+	opt.ZeroIndexed = true
+	p0 := New(opt)
+
+	// The same page in both modes yields the same offset.
+	for page := 1; page <= 10; page++ {
+		s1 := p1.New(page, 10)
+		s0 := p0.New(page-1, 10)
+		assert.Equal(t, s0.Offset, s1.Offset)
+		assert.Equal(t, s0.Page, page-1)
+	}
+
+	// The first page.
+	s := p0.New(-1, 10)
+	assert.Equal(t, s.Page, 0)
+	assert.Equal(t, s.Offset, 0)
+	s = p0.NewFromURL(url.Values{})
+	assert.Equal(t, s.Page, 0)
+	assert.Equal(t, s.Offset, 0)
+	s = p0.NewFromURL(url.Values{"page": []string{"2"}})
+	assert.Equal(t, s.Page, 2)
+	assert.Equal(t, s.Offset, 20)
+
+	// Page numbers.
+	s = p0.New(0, 10)
+	s.SetTotal(200)
+	assert.Equal(t, s.Pages, []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9})
+	assert.False(t, s.HasPrev())
+	assert.True(t, s.HasNext())
+	assert.Equal(t, s.TotalPages, 20)
+
+	s = p0.New(9, 10)
+	s.SetTotal(100)
+	assert.Equal(t, s.Offset, 90)
+	assert.True(t, s.HasPrev())
+	assert.False(t, s.HasNext())
+
+	// Out of range is snapped to the last page.
+	s = p0.New(50, 10)
+	s.SetTotal(100)
+	assert.Equal(t, s.Page, 9)
+	assert.Equal(t, s.Offset, 90)
+
+	// URLs.
+	assert.Equal(t, s.PageURL("/things", -1, nil), "/things?page=0")
+	assert.Equal(t, s.PageURL("/things", 100, nil), "/things?page=9")
+
+	s = p0.New(10, 5)
+	s.SetTotal(100)
+	out := s.HTML("/things", nil)
+	assert.Contains(t, out, `<a class="pg-page-first" href="/things?page=0&amp;per_page=5">0</a>`)
+	assert.Contains(t, out, `<a class="pg-page pg-selected" href="/things?page=10&amp;per_page=5">10</a>`)
+	assert.Contains(t, out, `<a class="pg-page-last" href="/things?page=19&amp;per_page=5">19</a>`)
+}
+
+func TestNewWithOptions(t *testing.T) {
+	// No options yields the defaults.
+	p := NewWithOptions()
+	assert.Equal(t, p.o, New(Default()).o)
+
+	p = NewWithOptions(WithMaxPerPage(100), WithPageParam("p"))
+	assert.Equal(t, p.o.MaxPerPage, 100)
+	assert.Equal(t, p.o.PageParam, "p")
+
+	// Omitted options retain their defaults.
+	d := Default()
+	assert.Equal(t, p.o.DefaultPerPage, d.DefaultPerPage)
+	assert.Equal(t, p.o.NumPageNums, d.NumPageNums)
+	assert.Equal(t, p.o.PerPageParam, d.PerPageParam)
+	assert.Equal(t, p.o.AllowAll, d.AllowAll)
+
+	s := p.NewFromURL(url.Values{"p": []string{"3"}, "per_page": []string{"80"}})
+	assert.Equal(t, s.Page, 3)
+	assert.Equal(t, s.PerPage, 80)
+	assert.Equal(t, s.Offset, 160)
+
+	p = NewWithOptions(WithDefaultPerPage(20), WithAllowAll(true), WithAllowAllParam("everything"))
+	s = p.NewFromURL(url.Values{})
+	assert.Equal(t, s.PerPage, 20)
+	s = p.NewFromURL(url.Values{"per_page": []string{"everything"}})
+	assert.Equal(t, s.PerPage, 0)
+}
+
+func TestTrailingWindow(t *testing.T) {
+	opt := Default()
+	opt.NumPageNums = 5
+	p := New(opt)
+
+	s := p.New(99, 1)
+	s.SetTotal(100)
+	assert.Equal(t, len(s.Pages), 5)
+	assert.Equal(t, s.Pages, []int{96, 97, 98, 99, 100})
+	assert.True(t, s.PinFirstPage)
+	assert.False(t, s.PinLastPage)
+
+	s = p.New(100, 1)
+	s.SetTotal(100)
+	assert.Equal(t, s.Pages, []int{96, 97, 98, 99, 100})
+}
+
+func TestKeepOutOfRangePage(t *testing.T) {
+	opt := Default()
+	p := New(opt)
+
+	// Clamped to the first page by default.
+	s := p.New(3, 10)
+	s.SetTotal(5)
+	assert.Equal(t, s.Page, 1)
+	assert.Equal(t, s.Offset, 0)
+	assert.False(t, s.OutOfRange)
+
+	s = p.New(3, 10)
+	s.SetTotal(0)
+	assert.Equal(t, s.Page, 1)
+	assert.False(t, s.OutOfRange)
+
+	// Requested page is retained.
+	opt.KeepOutOfRangePage = true
+	p = New(opt)
+	s = p.New(3, 10)
+	s.SetTotal(5)
+	assert.Equal(t, s.Page, 3)
+	assert.Equal(t, s.Offset, 20)
+	assert.True(t, s.OutOfRange)
+
+	s = p.New(3, 10)
+	s.SetTotal(0)
+	assert.Equal(t, s.Page, 3)
+	assert.True(t, s.OutOfRange)
+
+	s = p.New(1, 10)
+	s.SetTotal(5)
+	assert.Equal(t, s.Page, 1)
+	assert.False(t, s.OutOfRange)
+	// Beyond the last of multiple pages.
+	s = p.New(99, 10)
+	s.SetTotal(50)
+	assert.Equal(t, s.Page, 99)
+	assert.Equal(t, s.Offset, 980)
+	assert.True(t, s.OutOfRange)
+	assert.Equal(t, s.Summary(), "No results")
+
+	s = p.New(3, 10)
+	s.SetTotal(5)
+	assert.Equal(t, s.Summary(), "No results")
+
+	// Prev links point to the last page.
+	s = p.New(7, 10)
+	s.SetTotal(30)
+	assert.Equal(t, s.PrevPage(), 3)
+	assert.Equal(t, s.LinkHeader("/things", nil), `</things?page=1>; rel="first", </things?page=3>; rel="prev"`)
+
+	opt.ShowPrevNext = true
+	s = New(opt).New(7, 10)
+	s.SetTotal(30)
+	assert.Contains(t, s.HTML("/things", nil), `<a class="pg-prev" href="/things?page=3">`)
+	opt.ShowPrevNext = false
+
+	s = p.New(3, 10)
+	s.SetTotal(0)
+	assert.Equal(t, s.PrevPage(), 1)
+
+	opt.KeepOutOfRangePage = false
+	opt.OffsetOnly = true
+	s = New(opt).New(7, 10)
+	s.SetTotal(30)
+	assert.Equal(t, s.PrevPage(), 3)
+	opt.OffsetOnly = false
+	opt.KeepOutOfRangePage = true
+
+	// Clamped by default.
+	s = New(Default()).New(99, 10)
+	s.SetTotal(50)
+	assert.Equal(t, s.Page, 5)
+	assert.False(t, s.OutOfRange)
+}
+
+func TestClamped(t *testing.T) {
+	p := New(Default())
+
+	// In range.
+	s := p.New(5, 10)
+	s.SetTotal(100)
+	assert.False(t, s.Clamped())
+
+	s = p.NewFromURL(url.Values{})
+	s.SetTotal(100)
+	assert.False(t, s.Clamped())
+
+	// Over range.
+	s = p.New(999, 10)
+	s.SetTotal(100)
+	assert.Equal(t, s.Page, 10)
+	assert.True(t, s.Clamped())
+
+	s = p.New(3, 10)
+	s.SetTotal(5)
+	assert.Equal(t, s.Page, 1)
+	assert.True(t, s.Clamped())
+
+	// Under range.
+	s = p.New(-5, 10)
+	assert.Equal(t, s.Page, 1)
+	assert.True(t, s.Clamped())
+
+	s = p.NewFromURL(url.Values{"page": []string{"0"}})
+	assert.True(t, s.Clamped())
+}
+
+func TestNewFromMap(t *testing.T) {
+	opt := Default()
+	p := New(opt)
+
+	// float64 as decoded by encoding/json.
+	var m map[string]interface{}
+	assert.NoError(t, json.Unmarshal([]byte(`{"page": 3, "per_page": 20}`), &m))
+	s := p.NewFromMap(m)
+	assert.Equal(t, s.Page, 3)
+	assert.Equal(t, s.PerPage, 20)
+	assert.Equal(t, s.Offset, 40)
+
+	// Strings.
+	s = p.NewFromMap(map[string]interface{}{"page": "2", "per_page": "500"})
+	assert.Equal(t, s.Page, 2)
+	assert.Equal(t, s.PerPage, 50)
+
+	// Missing keys.
+	s = p.NewFromMap(map[string]interface{}{})
+	assert.Equal(t, s.Page, 1)
+	assert.Equal(t, s.PerPage, 10)
+	assert.False(t, s.Clamped())
+
+	s = p.NewFromMap(nil)
+	assert.Equal(t, s.Page, 1)
+	assert.Equal(t, s.PerPage, 10)
+
+	// All.
+	opt.AllowAll = true
+	p = New(opt)
+	s = p.NewFromMap(map[string]interface{}{"page": 1, "per_page": "all"})
+	assert.Equal(t, s.PerPage, 0)
+}
+
+func TestNewFromRequest(t *testing.T) {
+	p := New(Default())
+
+	r := httptest.NewRequest(http.MethodGet, "/things?page=3&per_page=20", nil)
+	s := p.NewFromRequest(r)
+	assert.Equal(t, s.Page, 3)
+	assert.Equal(t, s.PerPage, 20)
+
+	// Form values take precedence over the query.
+	r = httptest.NewRequest(http.MethodPost, "/things?page=3&per_page=20", strings.NewReader("page=4"))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	s = p.NewFromRequest(r)
+	assert.Equal(t, s.Page, 4)
+	assert.Equal(t, s.PerPage, 20)
+
+	// Non-form bodies are ignored.
+	r = httptest.NewRequest(http.MethodPost, "/things?page=3", strings.NewReader(`{"page": 4}`))
+	r.Header.Set("Content-Type", "application/json")
+	s = p.NewFromRequest(r)
+	assert.Equal(t, s.Page, 3)
+}
+
+func TestAllowedPerPage(t *testing.T) {
+	opt := Default()
+	opt.AllowedPerPage = []int{10, 25, 50, 100}
+	p := New(opt)
+
+	// In the list. MaxPerPage (50) is ignored.
+	s := p.NewFromURL(url.Values{"per_page": []string{"100"}})
+	assert.Equal(t, s.PerPage, 100)
+	s = p.NewFromURL(url.Values{"per_page": []string{"25"}})
+	assert.Equal(t, s.PerPage, 25)
+
+	// Not in the list.
+	s = p.NewFromURL(url.Values{"per_page": []string{"30"}})
+	assert.Equal(t, s.PerPage, 25)
+	s = p.NewFromURL(url.Values{"per_page": []string{"1000"}})
+	assert.Equal(t, s.PerPage, 100)
+	s = p.NewFromURL(url.Values{"per_page": []string{"1"}})
+	assert.Equal(t, s.PerPage, 10)
+
+	// Missing.
+	s = p.NewFromURL(url.Values{})
+	assert.Equal(t, s.PerPage, 10)
+
+	// AllowAll still permits all.
+	opt.AllowAll = true
+	p = New(opt)
+	s = p.NewFromURL(url.Values{"per_page": []string{"all"}})
+	assert.Equal(t, s.PerPage, 0)
+	s = p.NewFromURL(url.Values{"per_page": []string{"1000"}})
+	assert.Equal(t, s.PerPage, 100)
+}
+
+func TestPerPageSelectHTML(t *testing.T) {
+	p := New(Default())
+	s := p.New(3, 25)
+	s.SetTotal(100)
+
+	out := s.PerPageSelectHTML("/things", url.Values{"q": []string{"x"}}, []int{10, 25, 50})
+	assert.Equal(t, out,
+		`<select class="pg-per-page" name="per_page" onchange="window.location.href=this.options[this.selectedIndex].dataset.url">`+
+			`<option value="10" data-url="/things?page=1&amp;per_page=10&amp;q=x">10</option>`+
+			`<option value="25" data-url="/things?page=1&amp;per_page=25&amp;q=x" selected>25</option>`+
+			`<option value="50" data-url="/things?page=1&amp;per_page=50&amp;q=x">50</option>`+
+			`</select>`)
+	assert.Equal(t, strings.Count(out, " selected>"), 1)
+}
+
+func TestClone(t *testing.T) {
+	p := New(Default())
+	admin := p.Clone(func(o *Opt) {
+		o.AllowAll = true
+		o.MaxPerPage = 500
+	})
+
+	q := url.Values{"per_page": []string{"all"}}
+	assert.Equal(t, admin.NewFromURL(q).PerPage, 0)
+	assert.Equal(t, p.NewFromURL(q).PerPage, 10)
+
+	q.Set("per_page", "200")
+	assert.Equal(t, admin.NewFromURL(q).PerPage, 200)
+	assert.Equal(t, p.NewFromURL(q).PerPage, 50)
+
+	// The original options are untouched.
+	assert.Equal(t, p.o, New(Default()).o)
+
+	// Slices are not shared.
+	p = New(Opt{AllowedPerPage: []int{10, 20}})
+	c := p.Clone(func(o *Opt) {
+		o.AllowedPerPage[0] = 5
+	})
+	assert.Equal(t, p.o.AllowedPerPage, []int{10, 20})
+	assert.Equal(t, c.o.AllowedPerPage, []int{5, 20})
+
+	p = New(Opt{AllowedSortFields: []string{"id", "name"}})
+	c = p.Clone(func(o *Opt) {
+		o.AllowedSortFields[0] = "created_at"
+	})
+	assert.Equal(t, p.o.AllowedSortFields, []string{"id", "name"})
+	assert.Equal(t, c.o.AllowedSortFields, []string{"created_at", "name"})
+
+	// Maps are not shared.
+	p = New(Opt{PerPageAliases: map[string]int{"small": 10}})
+	c = p.Clone(func(o *Opt) {
+		o.PerPageAliases["small"] = 40
+	})
+	assert.Equal(t, p.o.PerPageAliases, map[string]int{"small": 10})
+	assert.Equal(t, c.o.PerPageAliases, map[string]int{"small": 40})
+}
+
+func TestTotalPages(t *testing.T) {
+	opt := Default()
+	p := New(opt)
+
+	for _, c := range []struct {
+		total int
+		pages int
+	}{
+		{0, 0},
+		{5, 1},
+		{10, 1},
+		{11, 2},
+		{100, 10},
+	} {
+		s := p.New(1, 10)
+		s.SetTotal(c.total)
+		assert.Equal(t, s.TotalPages, c.pages, c.total)
+	}
+
+	opt.AllowAll = true
+	p = New(opt)
+	s := p.New(1, -1)
+	s.SetTotal(100)
+	assert.Equal(t, s.TotalPages, 1)
+}
+
+func TestNewReverseFromURL(t *testing.T) {
+	opt := Default()
+	opt.Reverse = true
+	p := New(opt)
+
+	// No page starts at the last page.
+	s := p.NewReverseFromURL(url.Values{}, 195)
+	assert.Equal(t, s.Page, 20)
+	assert.Equal(t, s.Offset, 190)
+	assert.Equal(t, s.Limit, 10)
+	assert.Equal(t, s.TotalPages, 20)
+	assert.False(t, s.HasNext())
+	assert.True(t, s.HasPrev())
+	assert.Equal(t, s.Pages, []int{11, 12, 13, 14, 15, 16, 17, 18, 19, 20})
+	assert.True(t, s.PinFirstPage)
+	assert.False(t, s.PinLastPage)
+
+	// Sort params are retained.
+	opt.AllowedSortFields = []string{"name"}
+	s = New(opt).NewReverseFromURL(url.Values{"sort": []string{"name"}, "order": []string{"desc"}}, 195)
+	assert.Equal(t, s.Page, 20)
+	assert.Equal(t, s.Sort, "name")
+	assert.Equal(t, s.Order, "desc")
+
+	// Walking backwards.
+	s = p.NewReverseFromURL(url.Values{"page": []string{"9"}}, 95)
+	assert.Equal(t, s.Page, 9)
+	assert.Equal(t, s.Offset, 80)
+
+	// Small totals.
+	s = p.NewReverseFromURL(url.Values{}, 5)
+	assert.Equal(t, s.Page, 1)
+	assert.Equal(t, s.Offset, 0)
+	s = p.NewReverseFromURL(url.Values{}, 0)
+	assert.Equal(t, s.Page, 1)
+
+	// Zero indexed.
+	opt.ZeroIndexed = true
+	p = New(opt)
+	s = p.NewReverseFromURL(url.Values{"per_page": []string{"20"}}, 95)
+	assert.Equal(t, s.Page, 4)
+	assert.Equal(t, s.Offset, 80)
+
+	// Without Reverse, the first page is picked.
+	p = New(Default())
+	s = p.NewReverseFromURL(url.Values{}, 95)
+	assert.Equal(t, s.Page, 1)
+	assert.Equal(t, s.TotalPages, 10)
+}
+
+func TestMarshalJSON(t *testing.T) {
+	opt := Default()
+	opt.NumPageNums = 3
+	p := New(opt)
+
+	s := p.New(2, 10)
+	s.SetTotal(50)
+	b, err := json.Marshal(s)
+	assert.NoError(t, err)
+	assert.JSONEq(t, string(b), `{"page": 2, "per_page": 10, "total_pages": 5, "total": 50, "params": null}`)
+
+	opt.ExposePages = true
+	p = New(opt)
+	s = p.New(2, 10)
+	s.SetTotal(50)
+	b, err = json.Marshal(&s)
+	assert.NoError(t, err)
+	assert.JSONEq(t, string(b), `{"page": 2, "per_page": 10, "total_pages": 5, "total": 50, "params": null,
+		"pages": [1, 2, 3], "pin_first_page": false, "pin_last_page": true}`)
+
+	// Single page.
+	s = p.New(1, 10)
+	s.SetTotal(5)
+	b, err = json.Marshal(s)
+	assert.NoError(t, err)
+	assert.JSONEq(t, string(b), `{"page": 1, "per_page": 10, "total_pages": 1, "total": 5, "params": null,
+		"pages": [], "pin_first_page": false, "pin_last_page": false}`)
+}
+
+func TestOffsetFunc(t *testing.T) {
+	opt := Default()
+	opt.OffsetFunc = func(page, perPage int) int {
+		return (page-1)*perPage + 1
+	}
+	p := New(opt)
+
+	s := p.New(3, 10)
+	assert.Equal(t, s.Offset, 21)
+	assert.Equal(t, s.Limit, 10)
+
+	s = p.New(1, 10)
+	assert.Equal(t, s.Offset, 1)
+
+	// Snapping to the last page uses the function too.
+	s = p.New(50, 10)
+	s.SetTotal(100)
+	assert.Equal(t, s.Page, 10)
+	assert.Equal(t, s.Offset, 91)
+}
+
+func TestText(t *testing.T) {
+	opt := Default()
+	opt.NumPageNums = 3
+	p := New(opt)
+
+	s := p.New(5, 10)
+	s.SetTotal(200)
+	assert.Equal(t, s.Text(), "1 ... 4 [5] 6 ... 20")
+
+	s = p.New(1, 10)
+	s.SetTotal(200)
+	assert.Equal(t, s.Text(), "[1] 2 3 ... 20")
+
+	s = p.New(1, 10)
+	s.SetTotal(5)
+	assert.Equal(t, s.Text(), "")
+}
+
+func TestAccessibleHTML(t *testing.T) {
+	opt := Default()
+	opt.NumPageNums = 3
+	p := New(opt)
+
+	s := p.New(5, 10)
+	s.SetTotal(100)
+	out := s.HTML("/things", nil)
+	assert.NotContains(t, out, "<nav")
+	assert.NotContains(t, out, "aria-current")
+
+	opt.Accessible = true
+	p = New(opt)
+	s = p.New(5, 10)
+	s.SetTotal(100)
+	out = s.HTML("/things", nil)
+	assert.True(t, strings.HasPrefix(out, `<nav aria-label="Pagination">`))
+	assert.True(t, strings.HasSuffix(out, `</nav>`))
+	assert.Equal(t, strings.Count(out, `aria-current="page"`), 1)
+	assert.Contains(t, out, `<a class="pg-page pg-selected" aria-current="page" href="/things?page=5">5</a>`)
+}
+
+func TestEllipsisText(t *testing.T) {
+	opt := Default()
+	opt.NumPageNums = 3
+	opt.EllipsisText = "…"
+	p := New(opt)
+
+	s := p.New(5, 10)
+	s.SetTotal(100)
+	out := s.HTML("/things", nil)
+	assert.Contains(t, out, `<span class="pg-page-ellipsis-first">…</span>`)
+	assert.Contains(t, out, `<span class="pg-page-ellipsis-last">…</span>`)
+	assert.NotContains(t, out, "...")
+	assert.Equal(t, s.Text(), "1 … 4 [5] 6 … 10")
+
+	// Empty falls back to the default.
+	opt.EllipsisText = ""
+	p = New(opt)
+	s = p.New(5, 10)
+	s.SetTotal(100)
+	assert.Contains(t, s.HTML("/things", nil), `<span class="pg-page-ellipsis-first">...</span>`)
+}
+
+func TestHTMLPrevNext(t *testing.T) {
+	opt := Default()
+	opt.NumPageNums = 3
+	p := New(opt)
+
+	s := p.New(1, 10)
+	s.SetTotal(100)
+	assert.NotContains(t, s.HTML("/things", nil), "pg-prev")
+
+	opt.ShowPrevNext = true
+	p = New(opt)
+
+	// First page.
+	s = p.New(1, 10)
+	s.SetTotal(100)
+	out := s.HTML("/things", nil)
+	assert.True(t, strings.HasPrefix(out, `<span class="pg-prev pg-disabled">«</span> `))
+	assert.NotContains(t, out, `<a class="pg-prev"`)
+	assert.True(t, strings.HasSuffix(out, `<a class="pg-next" href="/things?page=2">»</a>`))
+
+	// Middle page.
+	s = p.New(5, 10)
+	s.SetTotal(100)
+	out = s.HTML("/things", nil)
+	assert.True(t, strings.HasPrefix(out, `<a class="pg-prev" href="/things?page=4">«</a> `))
+	assert.True(t, strings.HasSuffix(out, `<a class="pg-next" href="/things?page=6">»</a>`))
+
+	// Last page.
+	s = p.New(10, 10)
+	s.SetTotal(100)
+	out = s.HTML("/things", nil)
+	assert.True(t, strings.HasSuffix(out, `<span class="pg-next pg-disabled">»</span>`))
+
+	// Accessible.
+	opt.Accessible = true
+	p = New(opt)
+	s = p.New(5, 10)
+	s.SetTotal(100)
+	out = s.HTML("/things", nil)
+	assert.Contains(t, out, `<a class="pg-prev" aria-label="Previous page" href="/things?page=4">«</a> `)
+	assert.Contains(t, out, `<a class="pg-next" aria-label="Next page" href="/things?page=6">»</a>`)
+}
+
+func TestConstantWindow(t *testing.T) {
+	opt := Default()
+	opt.NumPageNums = 7
+	p := New(opt)
+
+	for _, c := range []struct {
+		page  int
+		pages []int
+	}{
+		{1, []int{1, 2, 3, 4, 5, 6, 7}},
+		{2, []int{1, 2, 3, 4, 5, 6, 7}},
+		{4, []int{1, 2, 3, 4, 5, 6, 7}},
+		{5, []int{2, 3, 4, 5, 6, 7, 8}},
+		{25, []int{22, 23, 24, 25, 26, 27, 28}},
+		{49, []int{44, 45, 46, 47, 48, 49, 50}},
+		{50, []int{44, 45, 46, 47, 48, 49, 50}},
+	} {
+		s := p.New(c.page, 10)
+		s.SetTotal(500)
+		assert.Equal(t, len(s.Pages), 7, c.page)
+		assert.Equal(t, s.Pages, c.pages, c.page)
+	}
+
+	// Fewer pages than the window.
+	s := p.New(2, 10)
+	s.SetTotal(30)
+	assert.Equal(t, s.Pages, []int{1, 2, 3})
+	assert.False(t, s.PinFirstPage)
+	assert.False(t, s.PinLastPage)
+
+	// Even window sizes.
+	opt.NumPageNums = 10
+	p = New(opt)
+	s = p.New(10, 10)
+	s.SetTotal(500)
+	assert.Equal(t, s.Pages, []int{5, 6, 7, 8, 9, 10, 11, 12, 13, 14})
+}
+
+func TestSetHasMore(t *testing.T) {
+	opt := Default()
+	opt.ShowPrevNext = true
+	p := New(opt)
+
+	s := p.New(3, 10)
+	assert.False(t, s.HasNext())
+
+	s.SetHasMore(true)
+	assert.True(t, s.HasNext())
+	assert.True(t, s.HasPrev())
+	assert.Equal(t, s.NextPage(), 4)
+	assert.Equal(t, s.LinkHeader("/things", nil),
+		`</things?page=1>; rel="first", `+
+			`</things?page=2>; rel="prev", `+
+			`</things?page=4>; rel="next"`)
+	assert.Equal(t, s.HTML("/things", nil),
+		`<a class="pg-prev" href="/things?page=2">«</a> `+
+			`<a class="pg-page pg-selected" href="/things?page=3">3</a> `+
+			`<a class="pg-next" href="/things?page=4">»</a>`)
+
+	// No more results.
+	s.SetHasMore(false)
+	assert.False(t, s.HasNext())
+	assert.NotContains(t, s.LinkHeader("/things", nil), `rel="next"`)
+	assert.NotContains(t, s.LinkHeader("/things", nil), `rel="last"`)
+}
+
+func TestSQLClause(t *testing.T) {
+	opt := Default()
+	p := New(opt)
+
+	s := p.New(3, 10)
+	assert.Equal(t, s.SQLClause(), "LIMIT 10 OFFSET 20")
+
+	s = p.New(1, 10)
+	assert.Equal(t, s.SQLClause(), "LIMIT 10 OFFSET 0")
+
+	opt.AllowAll = true
+	p = New(opt)
+	s = p.New(1, -1)
+	assert.Equal(t, s.SQLClause(), "")
+}
+
+func TestAlwaysShowEnds(t *testing.T) {
+	opt := Default()
+	opt.NumPageNums = 5
+	opt.AlwaysShowEnds = true
+	p := New(opt)
+
+	// The window starts at 1.
+	s := p.New(1, 10)
+	s.SetTotal(200)
+	assert.True(t, s.PinFirstPage)
+	assert.True(t, s.PinLastPage)
+	assert.Equal(t, s.Pages, []int{2, 3, 4, 5})
+	assert.Equal(t, s.Text(), "[1] 2 3 4 5 ... 20")
+
+	out := s.HTML("/things", nil)
+	assert.Equal(t, strings.Count(out, `href="/things?page=1"`), 1)
+	assert.Contains(t, out, `<a class="pg-page-first pg-selected" href="/things?page=1">1</a> <a class="pg-page" href="/things?page=2">2</a>`)
+	assert.NotContains(t, out, "pg-page-ellipsis-first")
+	assert.Contains(t, out, "pg-page-ellipsis-last")
+
+	// Adjacent to the first page.
+	s = p.New(4, 10)
+	s.SetTotal(200)
+	assert.Equal(t, s.Text(), "1 2 3 [4] 5 6 ... 20")
+
+	// Middle.
+	s = p.New(10, 10)
+	s.SetTotal(200)
+	assert.Equal(t, s.Text(), "1 ... 8 9 [10] 11 12 ... 20")
+
+	// The window ends at the last page.
+	s = p.New(20, 10)
+	s.SetTotal(200)
+	assert.Equal(t, s.Pages, []int{16, 17, 18, 19})
+	assert.Equal(t, s.Text(), "1 ... 16 17 18 19 [20]")
+	out = s.HTMLBootstrap("/things", nil)
+	assert.Equal(t, strings.Count(out, `href="/things?page=20"`), 1)
+	assert.Contains(t, out, `<li class="page-item active" aria-current="page"><a class="page-link" href="/things?page=20">20</a></li>`)
+
+	// Fewer pages than the window.
+	s = p.New(1, 10)
+	s.SetTotal(30)
+	assert.Equal(t, s.Text(), "[1] 2 3")
+	s = p.New(1, 10)
+	s.SetTotal(20)
+	assert.Equal(t, s.Text(), "[1] 2")
+}
+
+func TestNewFromURLWithParams(t *testing.T) {
+	p := New(Default())
+	q := url.Values{
+		"users_page":     []string{"2"},
+		"users_per_page": []string{"5"},
+		"posts_page":     []string{"3"},
+		"page":           []string{"9"},
+	}
+
+	users := p.NewFromURLWithParams(q, "users_page", "users_per_page")
+	users.SetTotal(100)
+	assert.Equal(t, users.Page, 2)
+	assert.Equal(t, users.PerPage, 5)
+	assert.Equal(t, users.Offset, 5)
+
+	posts := p.NewFromURLWithParams(q, "posts_page", "posts_per_page")
+	posts.SetTotal(100)
+	assert.Equal(t, posts.Page, 3)
+	assert.Equal(t, posts.PerPage, 10)
+	assert.Equal(t, posts.Offset, 20)
+
+	// URLs use the overridden param names.
+	assert.Equal(t, users.PageURL("/", 4, nil), "/?users_page=4&users_per_page=5")
+	assert.Equal(t, posts.PageURL("/", 4, url.Values{"users_page": []string{"2"}}), "/?posts_page=4&users_page=2")
+	assert.Contains(t, posts.HTML("/", nil), `href="/?posts_page=2"`)
+	assert.Contains(t, users.PerPageSelectHTML("/", nil, []int{5}), `name="users_per_page"`)
+}
+
+func TestSetTotalTwice(t *testing.T) {
+	opt := Default()
+	opt.NumPageNums = 3
+	p := New(opt)
+
+	s := p.New(2, 10)
+	s.SetTotal(100)
+	assert.Equal(t, s.TotalPages, 10)
+	assert.Equal(t, s.Pages, []int{1, 2, 3})
+	assert.True(t, s.PinLastPage)
+
+	s.SetTotal(30)
+	assert.Equal(t, s.Total, 30)
+	assert.Equal(t, s.TotalPages, 3)
+	assert.Equal(t, s.Pages, []int{1, 2, 3})
+	assert.False(t, s.PinFirstPage)
+	assert.False(t, s.PinLastPage)
+
+	s.SetTotal(5)
+	assert.Equal(t, s.TotalPages, 1)
+	assert.Nil(t, s.Pages)
+	assert.False(t, s.PinLastPage)
+}
+
+func TestWindow(t *testing.T) {
+	opt := Default()
+	opt.NumPageNums = 5
+	p := New(opt)
+
+	s := p.New(1, 10)
+	first, last := s.Window()
+	assert.Equal(t, first, 1)
+	assert.Equal(t, last, 1)
+
+	s.SetTotal(5)
+	first, last = s.Window()
+	assert.Equal(t, first, 1)
+	assert.Equal(t, last, 1)
+
+	s = p.New(10, 10)
+	s.SetTotal(200)
+	first, last = s.Window()
+	assert.Equal(t, first, 8)
+	assert.Equal(t, last, 12)
+}
+
+func TestPerPageAliases(t *testing.T) {
+	opt := Default()
+	opt.AllowAll = true
+	opt.PerPageAliases = map[string]int{"small": 5, "medium": 25}
+	p := New(opt)
+
+	s := p.NewFromURL(url.Values{"per_page": []string{"medium"}})
+	assert.Equal(t, s.PerPage, 25)
+
+	s = p.NewFromURL(url.Values{"per_page": []string{"huge"}})
+	assert.Equal(t, s.PerPage, 10)
+
+	s = p.NewFromURL(url.Values{"per_page": []string{"20"}})
+	assert.Equal(t, s.PerPage, 20)
+
+	s = p.NewFromURL(url.Values{"per_page": []string{"all"}})
+	assert.Equal(t, s.PerPage, 0)
+}
+
+func TestResolve(t *testing.T) {
+	p := New(Default())
+	count := func(ctx context.Context) (int, error) {
+		return 100, nil
+	}
+
+	s := p.New(2, 10)
+	assert.NoError(t, s.Resolve(context.Background(), count))
+	assert.Equal(t, s.Total, 100)
+	assert.Equal(t, s.TotalPages, 10)
+
+	// Count errors.
+	errCount := errors.New("count failed")
+	s = p.New(2, 10)
+	assert.Equal(t, s.Resolve(context.Background(), func(ctx context.Context) (int, error) {
+		return 0, errCount
+	}), errCount)
+
+	// Canceled context.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	s = p.New(2, 10)
+	assert.Equal(t, s.Resolve(ctx, count), context.Canceled)
+	assert.Equal(t, s.Total, 0)
+}
+
+type failWriter struct{}
+
+func (failWriter) Write([]byte) (int, error) {
+	return 0, errors.New("write failed")
+}
+
+func TestWriteHTML(t *testing.T) {
+	opt := Default()
+	opt.ShowPrevNext = true
+	p := New(opt)
+
+	s := p.New(5, 10)
+	s.SetTotal(200)
+
+	var b bytes.Buffer
+	n, err := s.WriteHTML(&b, "/things", url.Values{"q": []string{"x"}})
+	assert.NoError(t, err)
+	assert.Equal(t, n, b.Len())
+	assert.Equal(t, b.String(), s.HTML("/things", url.Values{"q": []string{"x"}}))
+
+	_, err = s.WriteHTML(failWriter{}, "/things", nil)
+	assert.Error(t, err)
+
+	// Template.
+	opt.HTMLTemplate = template.Must(template.New("pg").Parse(`{{ range .Pages }}[{{ .Num }}]{{ end }}`))
+	p = New(opt)
+	s = p.New(1, 10)
+	s.SetTotal(30)
+
+	b.Reset()
+	n, err = s.WriteHTML(&b, "/things", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, n, 9)
+	assert.Equal(t, b.String(), "[1][2][3]")
+	assert.Equal(t, s.HTML("/things", nil), "[1][2][3]")
+
+	_, err = s.WriteHTML(failWriter{}, "/things", nil)
+	assert.Error(t, err)
+}
+
+func TestHTMLSafe(t *testing.T) {
+	opt := Default()
+	opt.NumPageNums = 3
+	p := New(opt)
+
+	s := p.New(1, 10)
+	s.SetTotal(30)
+
+	tpl := template.Must(template.New("page").Parse(`<div>{{ .HTMLSafe "/things" .Params }}</div>`))
+	var b bytes.Buffer
+	assert.NoError(t, tpl.Execute(&b, struct {
+		*Set
+		Params url.Values
+	}{&s, url.Values{"q": []string{`"><script>`}}}))
+	assert.Equal(t, b.String(), `<div>`+
+		`<a class="pg-page pg-selected" href="/things?page=1&amp;q=%22%3E%3Cscript%3E">1</a> `+
+		`<a class="pg-page" href="/things?page=2&amp;q=%22%3E%3Cscript%3E">2</a> `+
+		`<a class="pg-page" href="/things?page=3&amp;q=%22%3E%3Cscript%3E">3</a>`+
+		`</div>`)
+
+	// The URI is escaped.
+	assert.Contains(t, string(s.HTMLSafe(`/things"><script>`, nil)), `href="/things&#34;&gt;&lt;script&gt;?page=1"`)
+	assert.Contains(t, string(s.HTMLSafe("/a?x=1&y=2", nil)), `href="/a?x=1&amp;y=2?page=1"`)
+
+	// With a template, the URI is only escaped once by html/template.
+	opt.HTMLTemplate = template.Must(template.New("pg").Parse(`{{ range .Pages }}<a href="{{ .URL }}">{{ .Num }}</a>{{ end }}`))
+	p = New(opt)
+	s = p.New(1, 10)
+	s.SetTotal(20)
+	assert.Equal(t, string(s.HTMLSafe("/a?x=1&y=2", nil)),
+		`<a href="/a?x=1&amp;y=2?page=1">1</a><a href="/a?x=1&amp;y=2?page=2">2</a>`)
+}
+
+func TestDescendingPages(t *testing.T) {
+	opt := Default()
+	opt.NumPageNums = 3
+	opt.DescendingPages = true
+	opt.ShowPrevNext = true
+	p := New(opt)
+
+	s := p.New(5, 10)
+	s.SetTotal(200)
+	assert.Equal(t, s.Pages, []int{6, 5, 4})
+	assert.True(t, s.PinFirstPage)
+	assert.True(t, s.PinLastPage)
+	assert.Equal(t, s.Text(), "20 ... 6 [5] 4 ... 1")
+
+	assert.Equal(t, s.HTML("/things", nil),
+		`<a class="pg-next" href="/things?page=6">»</a> `+
+			`<a class="pg-page-last" href="/things?page=20">20</a> `+
+			`<span class="pg-page-ellipsis-last">...</span> `+
+			`<a class="pg-page" href="/things?page=6">6</a> `+
+			`<a class="pg-page pg-selected" href="/things?page=5">5</a> `+
+			`<a class="pg-page" href="/things?page=4">4</a> `+
+			`<span class="pg-page-ellipsis-first">...</span> `+
+			`<a class="pg-page-first" href="/things?page=1">1</a> `+
+			`<a class="pg-prev" href="/things?page=4">«</a>`)
+
+	out := s.HTMLBootstrap("/things", nil)
+	assert.True(t, strings.HasPrefix(out, `<ul class="pagination"><li class="page-item"><a class="page-link" href="/things?page=6">»</a></li>`+
+		`<li class="page-item"><a class="page-link" href="/things?page=20">20</a></li>`))
+
+	// The first page.
+	s = p.New(1, 10)
+	s.SetTotal(200)
+	assert.Equal(t, s.Pages, []int{3, 2, 1})
+	assert.False(t, s.PinFirstPage)
+	assert.Equal(t, s.Text(), "20 ... 3 2 [1]")
+}
+
+func TestRange(t *testing.T) {
+	opt := Default()
+	p := New(opt)
+
+	// Full page.
+	s := p.New(2, 10)
+	start, end := s.Range(25)
+	assert.Equal(t, start, 10)
+	assert.Equal(t, end, 20)
+
+	// Partial last page.
+	s = p.New(3, 10)
+	start, end = s.Range(25)
+	assert.Equal(t, start, 20)
+	assert.Equal(t, end, 25)
+
+	// Beyond the total.
+	s = p.New(5, 10)
+	start, end = s.Range(25)
+	assert.Equal(t, start, 25)
+	assert.Equal(t, end, 25)
+
+	// All.
+	opt.AllowAll = true
+	p = New(opt)
+	s = p.New(1, -1)
+	start, end = s.Range(25)
+	assert.Equal(t, start, 0)
+	assert.Equal(t, end, 25)
+}
+
+func TestMaxPage(t *testing.T) {
+	opt := Default()
+	p := New(opt)
+
+	s := p.New(10000, 10)
+	assert.Equal(t, s.Page, 10000)
+	assert.False(t, s.PageCapped)
+
+	opt.MaxPage = 100
+	p = New(opt)
+	s = p.New(10000, 10)
+	assert.Equal(t, s.Page, 100)
+	assert.Equal(t, s.Offset, 990)
+	assert.True(t, s.PageCapped)
+	assert.True(t, s.Clamped())
+
+	s = p.New(100, 10)
+	assert.Equal(t, s.Page, 100)
+	assert.False(t, s.PageCapped)
+
+	s = p.NewFromURL(url.Values{"page": []string{"101"}})
+	assert.Equal(t, s.Page, 100)
+	assert.True(t, s.PageCapped)
+}
+
+func TestSEOLinks(t *testing.T) {
+	p := New(Default())
+
+	// First page.
+	s := p.New(1, 10)
+	s.SetTotal(100)
+	assert.Equal(t, s.SEOLinks("/things", nil),
+		`<link rel="canonical" href="/things?page=1">`+"\n"+
+			`<link rel="next" href="/things?page=2">`)
+
+	// Middle page.
+	s = p.New(5, 10)
+	s.SetTotal(100)
+	assert.Equal(t, s.SEOLinks("/things", url.Values{"q": []string{"x"}}),
+		`<link rel="canonical" href="/things?page=5&q=x">`+"\n"+
+			`<link rel="prev" href="/things?page=4&q=x">`+"\n"+
+			`<link rel="next" href="/things?page=6&q=x">`)
+
+	// Last page.
+	s = p.New(10, 10)
+	s.SetTotal(100)
+	assert.Equal(t, s.SEOLinks("/things", nil),
+		`<link rel="canonical" href="/things?page=10">`+"\n"+
+			`<link rel="prev" href="/things?page=9">`)
+}
+
+func TestNewFromOffsetLimit(t *testing.T) {
+	opt := Default()
+	p := New(opt)
+
+	s := p.NewFromOffsetLimit(40, 20)
+	assert.Equal(t, s.Page, 3)
+	assert.Equal(t, s.PerPage, 20)
+	assert.Equal(t, s.Offset, 40)
+	assert.Equal(t, s.Limit, 20)
+
+	// Offset not divisible by the limit.
+	s = p.NewFromOffsetLimit(45, 20)
+	assert.Equal(t, s.Page, 3)
+	assert.Equal(t, s.Offset, 45)
+
+	// Invalid values.
+	s = p.NewFromOffsetLimit(-5, 0)
+	assert.Equal(t, s.Page, 1)
+	assert.Equal(t, s.Offset, 0)
+	assert.Equal(t, s.Limit, 10)
+
+	s = p.NewFromOffsetLimit(100, 500)
+	assert.Equal(t, s.Limit, 50)
+	assert.Equal(t, s.Page, 3)
+
+	// URL.
+	s = p.NewFromURLOffset(url.Values{"offset": []string{"30"}, "limit": []string{"15"}})
+	assert.Equal(t, s.Page, 3)
+	assert.Equal(t, s.Offset, 30)
+	assert.Equal(t, s.Limit, 15)
+
+	opt.OffsetParam = "skip"
+	opt.LimitParam = "take"
+	opt.AllowAll = true
+	p = New(opt)
+	s = p.NewFromURLOffset(url.Values{"skip": []string{"7"}, "take": []string{"5"}})
+	assert.Equal(t, s.Page, 2)
+	assert.Equal(t, s.Offset, 7)
+	s = p.NewFromURLOffset(url.Values{"take": []string{"all"}})
+	assert.Equal(t, s.Page, 1)
+	assert.Equal(t, s.Limit, 0)
+}
+
+func TestMarkdown(t *testing.T) {
+	opt := Default()
+	opt.NumPageNums = 3
+	p := New(opt)
+
+	s := p.New(5, 10)
+	s.SetTotal(200)
+	assert.Equal(t, s.Markdown("/things", url.Values{"q": []string{"x"}}),
+		"[1](/things?page=1&q=x) ... [4](/things?page=4&q=x) **5** [6](/things?page=6&q=x) ... [20](/things?page=20&q=x)")
+
+	s = p.New(1, 10)
+	s.SetTotal(200)
+	assert.Equal(t, s.Markdown("/things", nil), "**1** [2](/things?page=2) [3](/things?page=3) ... [20](/things?page=20)")
+}
+
+func TestNewFromURLStrict(t *testing.T) {
+	opt := Default()
+	opt.AllowAll = true
+	p := New(opt)
+
+	// Invalid.
+	_, err := p.NewFromURLStrict(url.Values{"page": []string{"abc"}})
+	assert.True(t, errors.Is(err, ErrInvalidParam))
+	assert.Contains(t, err.Error(), "page")
+
+	_, err = p.NewFromURLStrict(url.Values{"per_page": []string{"abc"}})
+	assert.True(t, errors.Is(err, ErrInvalidParam))
+	assert.Contains(t, err.Error(), "per_page")
+
+	// Empty.
+	s, err := p.NewFromURLStrict(url.Values{})
+	assert.NoError(t, err)
+	assert.Equal(t, s.Page, 1)
+	assert.Equal(t, s.PerPage, 10)
+
+	s, err = p.NewFromURLStrict(url.Values{"page": []string{""}})
+	assert.NoError(t, err)
+	assert.Equal(t, s.Page, 1)
+
+	// Valid.
+	s, err = p.NewFromURLStrict(url.Values{"page": []string{"3"}, "per_page": []string{"20"}})
+	assert.NoError(t, err)
+	assert.Equal(t, s.Page, 3)
+	assert.Equal(t, s.PerPage, 20)
+
+	s, err = p.NewFromURLStrict(url.Values{"per_page": []string{"all"}})
+	assert.NoError(t, err)
+	assert.Equal(t, s.PerPage, 0)
+}
+
+func TestNegativeMeansAll(t *testing.T) {
+	opt := Default()
+	opt.NegativeMeansAll = true
+	p := New(opt)
+
+	// Direct calls.
+	s := p.New(1, -1)
+	assert.Equal(t, s.PerPage, 0)
+	assert.Equal(t, s.Limit, 0)
+
+	// Requests are restricted.
+	s = p.NewFromURL(url.Values{"per_page": []string{"-1"}})
+	assert.Equal(t, s.PerPage, 10)
+	s = p.NewFromURL(url.Values{"per_page": []string{"all"}})
+	assert.Equal(t, s.PerPage, 10)
+	s = p.NewFromMap(map[string]interface{}{"per_page": -1})
+	assert.Equal(t, s.PerPage, 10)
+	s = p.NewFromURLOffset(url.Values{"limit": []string{"-1"}})
+	assert.Equal(t, s.PerPage, 10)
+
+	// Without the option.
+	p = New(Default())
+	s = p.New(1, -1)
+	assert.Equal(t, s.PerPage, 10)
+
+	// AllowAll applies to both.
+	opt.AllowAll = true
+	p = New(opt)
+	s = p.NewFromURL(url.Values{"per_page": []string{"-1"}})
+	assert.Equal(t, s.PerPage, 0)
+	s = p.New(1, -1)
+	assert.Equal(t, s.PerPage, 0)
+}
+
+func TestSetHeaders(t *testing.T) {
+	p := New(Default())
+
+	s := p.New(2, 10)
+	s.SetTotal(95)
+	h := http.Header{}
+	s.SetHeaders(h)
+	assert.Equal(t, h.Get("X-Total-Count"), "95")
+	assert.Equal(t, h.Get("X-Total-Pages"), "10")
+	assert.Equal(t, h.Get("X-Page"), "2")
+	assert.Equal(t, h.Get("X-Per-Page"), "10")
+
+	// Unknown total.
+	s = p.New(2, 10)
+	h = http.Header{}
+	s.SetHeaders(h)
+	assert.Equal(t, h.Get("X-Page"), "2")
+	assert.Empty(t, h.Values("X-Total-Count"))
+	assert.Empty(t, h.Values("X-Total-Pages"))
+}
+
+func TestClasses(t *testing.T) {
+	opt := Default()
+	opt.NumPageNums = 3
+	opt.ShowPrevNext = true
+	opt.Classes = Classes{Selected: "active", Page: "page"}
+	p := New(opt)
+
+	s := p.New(5, 10)
+	s.SetTotal(200)
+	out := s.HTML("/things", nil)
+	assert.Contains(t, out, `<a class="page active" href="/things?page=5">5</a>`)
+	assert.Equal(t, strings.Count(out, "active"), 1)
+	assert.NotContains(t, out, "pg-selected")
+
+	// Empty class names fall back to the defaults.
+	assert.Contains(t, out, `<a class="pg-page-first" href="/things?page=1">1</a>`)
+	assert.Contains(t, out, `<span class="pg-page-ellipsis-last">...</span>`)
+	assert.Contains(t, out, `<a class="pg-prev" href="/things?page=4">`)
+}
+
+func TestLinks(t *testing.T) {
+	opt := Default()
+	opt.NumPageNums = 3
+	p := New(opt)
+
+	s := p.New(5, 10)
+	s.SetTotal(200)
+	assert.Equal(t, s.Links("/things", nil), []PageLink{
+		{Page: 1, URL: "/things?page=1"},
+		{Ellipsis: true},
+		{Page: 4, URL: "/things?page=4"},
+		{Page: 5, URL: "/things?page=5", Current: true},
+		{Page: 6, URL: "/things?page=6"},
+		{Ellipsis: true},
+		{Page: 20, URL: "/things?page=20"},
+	})
+
+	// No pins and no ellipses when all the pages fit in the window.
+	s = p.New(2, 10)
+	s.SetTotal(30)
+	for _, l := range s.Links("/things", nil) {
+		assert.False(t, l.Ellipsis)
+	}
+	assert.Equal(t, len(s.Links("/things", nil)), 3)
+}
+
+func TestHTMLQueryParamsUnchanged(t *testing.T) {
+	p := New(Default())
+	s := p.New(5, 10)
+	s.SetTotal(200)
+
+	qp := url.Values{"q": []string{"search"}, "sort": []string{"asc"}}
+	a := s.HTML("/things", qp)
+	b := s.HTML("/things", qp)
+	assert.Equal(t, a, b)
+	assert.Equal(t, qp, url.Values{"q": []string{"search"}, "sort": []string{"asc"}})
+	assert.Contains(t, a, `href="/things?page=6&amp;q=search&amp;sort=asc"`)
+}
+
+func TestQueryParamsNotMutated(t *testing.T) {
+	p := New(Default())
+	s := p.New(2, 10)
+	s.SetTotal(100)
+
+	qp := url.Values{"q": []string{"search"}}
+	s.HTML("/things", qp)
+	s.LinkHeader("/things", qp)
+	s.PerPageSelectHTML("/things", qp, []int{10, 20})
+	_, ok := qp["page"]
+	assert.False(t, ok)
+	_, ok = qp["per_page"]
+	assert.False(t, ok)
+	assert.Equal(t, qp.Get("q"), "search")
+}
+
+func TestAllPageURLs(t *testing.T) {
+	p := New(Default())
+	s := p.New(1, 10)
+	s.SetTotal(25)
+	assert.Equal(t, s.AllPageURLs("/things", nil), []string{
+		"/things?page=1",
+		"/things?page=2",
+		"/things?page=3",
+	})
+
+	// Bounded.
+	s.SetTotal(1000000)
+	u := s.AllPageURLs("/things", url.Values{"q": []string{"x"}}, 2)
+	assert.Equal(t, u, []string{"/things?page=1&q=x", "/things?page=2&q=x"})
+
+	// No total.
+	s = p.New(1, 10)
+	assert.Equal(t, len(s.AllPageURLs("/things", nil)), 0)
+}
+
+func TestWindowAlign(t *testing.T) {
+	for _, c := range []struct {
+		align string
+		page  int
+		exp   []int
+	}{
+		{"", 10, []int{8, 9, 10, 11, 12}},
+		{AlignCenter, 10, []int{8, 9, 10, 11, 12}},
+		{AlignLeft, 10, []int{10, 11, 12, 13, 14}},
+		{AlignRight, 10, []int{6, 7, 8, 9, 10}},
+
+		// Shifted at the ends.
+		{AlignLeft, 19, []int{16, 17, 18, 19, 20}},
+		{AlignRight, 2, []int{1, 2, 3, 4, 5}},
+	} {
+		opt := Default()
+		opt.NumPageNums = 5
+		opt.WindowAlign = c.align
+		p := New(opt)
+
+		s := p.New(c.page, 10)
+		s.SetTotal(200)
+		assert.Equal(t, s.Pages, c.exp, c.align)
+	}
+}
+
+func TestParse(t *testing.T) {
+	p := New(Default())
+	q := url.Values{
+		"page":     []string{"2"},
+		"per_page": []string{"20"},
+		"q":        []string{"search"},
+	}
+	s, rest := p.Parse(q)
+	assert.Equal(t, s.Page, 2)
+	assert.Equal(t, s.PerPage, 20)
+	assert.Equal(t, rest, url.Values{"q": []string{"search"}})
+
+	// The original params are untouched.
+	assert.Equal(t, q.Get("page"), "2")
+	assert.Equal(t, q.Get("per_page"), "20")
+}
+
+func TestNewWithMax(t *testing.T) {
+	p := New(Default())
+
+	s := p.NewWithMax(1, 500, 1000)
+	assert.Equal(t, s.PerPage, 500)
+	assert.Equal(t, s.Limit, 500)
+
+	s = p.NewWithMax(1, 5000, 1000)
+	assert.Equal(t, s.PerPage, 1000)
+
+	// Falls back to Opt.MaxPerPage.
+	s = p.NewWithMax(1, 500, 0)
+	assert.Equal(t, s.PerPage, 50)
+
+	// The global max is unaffected.
+	s = p.New(1, 500)
+	assert.Equal(t, s.PerPage, 50)
+}
+
+func TestIsFirstIsLast(t *testing.T) {
+	p := New(Default())
+
+	s := p.New(1, 10)
+	assert.True(t, s.IsFirst())
+	assert.False(t, s.IsLast())
+
+	s.SetTotal(100)
+	assert.True(t, s.IsFirst())
+	assert.False(t, s.IsLast())
+
+	s = p.New(10, 10)
+	s.SetTotal(100)
+	assert.False(t, s.IsFirst())
+	assert.True(t, s.IsLast())
+
+	s = p.New(5, 10)
+	s.SetTotal(100)
+	assert.False(t, s.IsFirst())
+	assert.False(t, s.IsLast())
+
+	// Single and empty results.
+	for _, total := range []int{0, 5} {
+		s = p.New(1, 10)
+		s.SetTotal(total)
+		assert.True(t, s.IsFirst())
+		assert.True(t, s.IsLast())
+	}
+
+	// Zero indexed.
+	o := Default()
+	o.ZeroIndexed = true
+	p = New(o)
+	s = p.New(0, 10)
+	s.SetTotal(100)
+	assert.True(t, s.IsFirst())
+	s = p.New(9, 10)
+	s.SetTotal(100)
+	assert.True(t, s.IsLast())
+}
+
+func TestCurrentPageCount(t *testing.T) {
+	p := New(Default())
+
+	s := p.New(2, 10)
+	assert.Equal(t, s.CurrentPageCount(), 0)
+
+	s.SetTotal(95)
+	assert.Equal(t, s.CurrentPageCount(), 10)
+
+	s = p.New(10, 10)
+	s.SetTotal(95)
+	assert.Equal(t, s.CurrentPageCount(), 5)
+
+	s = p.New(1, 10)
+	s.SetTotal(0)
+	assert.Equal(t, s.CurrentPageCount(), 0)
+
+	// All.
+	o := Default()
+	o.AllowAll = true
+	s = New(o).New(1, -1)
+	s.SetTotal(95)
+	assert.Equal(t, s.CurrentPageCount(), 95)
+}
+
+func TestOffsetOnly(t *testing.T) {
+	o := Default()
+	o.OffsetOnly = true
+	p := New(o)
+
+	s := p.New(3, 10)
+	s.SetTotal(5)
+	assert.Equal(t, s.Page, 3)
+	assert.Equal(t, s.Offset, 20)
+	assert.Equal(t, s.TotalPages, 1)
+
+	s = p.New(30, 10)
+	s.SetTotal(100)
+	assert.Equal(t, s.Page, 30)
+	assert.Equal(t, s.Offset, 290)
+	assert.Equal(t, s.TotalPages, 10)
+
+	// Without OffsetOnly.
+	s = New(Default()).New(3, 10)
+	s.SetTotal(5)
+	assert.Equal(t, s.Page, 1)
+	assert.Equal(t, s.Offset, 0)
+}
+
+func TestHTMLParams(t *testing.T) {
+	o := Default()
+	o.NumPageNums = 1
+	p := New(o)
+
+	// Only SetParams.
+	s := p.New(1, 10)
+	s.SetTotal(20)
+	s.SetParams(url.Values{"q": []string{"a"}})
+	assert.Contains(t, s.HTML("/things", nil), `href="/things?page=2&amp;q=a"`)
+
+	// Only the HTML arg.
+	s = p.New(1, 10)
+	s.SetTotal(20)
+	assert.Contains(t, s.HTML("/things", url.Values{"q": []string{"b"}}), `href="/things?page=2&amp;q=b"`)
+
+	// Both, with qp taking precedence.
+	s = p.New(1, 10)
+	s.SetTotal(20)
+	s.SetParams(url.Values{"q": []string{"a"}, "sort": []string{"asc"}})
+	out := s.HTML("/things", url.Values{"q": []string{"b"}})
+	assert.Contains(t, out, `href="/things?page=2&amp;q=b&amp;sort=asc"`)
+	assert.NotContains(t, out, "q=a")
+	assert.Equal(t, s.Params, url.Values{"q": []string{"a"}, "sort": []string{"asc"}})
+}
+
+func TestJSONAPILinks(t *testing.T) {
+	p := New(Default())
+	str := func(s string) *string { return &s }
+
+	s := p.New(1, 10)
+	s.SetTotal(30)
+	assert.Equal(t, s.JSONAPILinks("/things", nil), map[string]*string{
+		"self":  str("/things?page=1"),
+		"first": str("/things?page=1"),
+		"last":  str("/things?page=3"),
+		"prev":  nil,
+		"next":  str("/things?page=2"),
+	})
+
+	s = p.New(3, 10)
+	s.SetTotal(30)
+	l := s.JSONAPILinks("/things", nil)
+	assert.Nil(t, l["next"])
+	assert.Equal(t, *l["prev"], "/things?page=2")
+
+	b, err := json.Marshal(l)
+	assert.Nil(t, err)
+	assert.Contains(t, string(b), `"next":null`)
+}
+
+func TestLoadMoreURL(t *testing.T) {
+	p := New(Default())
+
+	s := p.New(1, 10)
+	s.SetTotal(30)
+	u, ok := s.LoadMoreURL("/things", url.Values{"q": []string{"a"}})
+	assert.True(t, ok)
+	assert.Equal(t, u, "/things?page=2&q=a")
+
+	s = p.New(3, 10)
+	s.SetTotal(30)
+	u, ok = s.LoadMoreURL("/things", nil)
+	assert.False(t, ok)
+	assert.Equal(t, u, "")
+
+	// Unknown total.
+	s = p.New(3, 10)
+	s.SetHasMore(true)
+	u, ok = s.LoadMoreURL("/things", nil)
+	assert.True(t, ok)
+	assert.Equal(t, u, "/things?page=4")
+}
+
+func TestMinPerPage(t *testing.T) {
+	o := Default()
+	o.DefaultPerPage = 2
+	o.MinPerPage = 5
+	p := New(o)
+
+	s := p.New(1, 3)
+	assert.Equal(t, s.PerPage, 5)
+	assert.Equal(t, s.Limit, 5)
+
+	s = p.New(1, 20)
+	assert.Equal(t, s.PerPage, 20)
+
+	// Absent values fall back to the default.
+	s = p.New(1, 0)
+	assert.Equal(t, s.PerPage, 2)
+
+	// MinPerPage is capped at MaxPerPage.
+	o.MinPerPage = 100
+	s = New(o).New(1, 3)
+	assert.Equal(t, s.PerPage, 50)
+}
+
+func TestRequested(t *testing.T) {
+	p := New(Default())
+
+	s := p.NewFromURL(url.Values{"page": []string{"-3"}, "per_page": []string{"500"}})
+	assert.Equal(t, s.Page, 1)
+	assert.Equal(t, s.PerPage, 50)
+	assert.Equal(t, s.RequestedPage, -3)
+	assert.Equal(t, s.RequestedPerPage, 500)
+
+	// Negative and all values are retained as requested.
+	s = p.NewFromURL(url.Values{"per_page": []string{"-5"}})
+	assert.Equal(t, s.PerPage, 10)
+	assert.Equal(t, s.RequestedPerPage, -5)
+
+	s = p.NewFromURL(url.Values{"per_page": []string{"all"}})
+	assert.Equal(t, s.PerPage, 10)
+	assert.Equal(t, s.RequestedPerPage, -1)
+
+	s = p.New(20, 10)
+	s.SetTotal(50)
+	assert.Equal(t, s.Page, 5)
+	assert.Equal(t, s.RequestedPage, 20)
+	assert.Equal(t, s.RequestedPerPage, 10)
+	assert.True(t, s.Clamped())
+}
+
+func TestSortOrder(t *testing.T) {
+	o := Default()
+	o.AllowedSortFields = []string{"name", "created_at"}
+	o.DefaultSort = "id"
+	p := New(o)
+
+	// Allowed.
+	s := p.NewFromURL(url.Values{"sort": []string{"name"}, "order": []string{"DESC"}})
+	assert.Equal(t, s.Sort, "name")
+	assert.Equal(t, s.Order, "desc")
+
+	// Disallowed.
+	s = p.NewFromURL(url.Values{"sort": []string{"password; DROP TABLE"}, "order": []string{"sideways"}})
+	assert.Equal(t, s.Sort, "id")
+	assert.Equal(t, s.Order, "asc")
+
+	// Default.
+	s = p.NewFromURL(url.Values{})
+	assert.Equal(t, s.Sort, "id")
+	assert.Equal(t, s.Order, "asc")
+
+	// Custom param names.
+	o.SortParam = "s"
+	o.OrderParam = "o"
+	s = New(o).NewFromURL(url.Values{"s": []string{"created_at"}, "o": []string{"desc"}})
+	assert.Equal(t, s.Sort, "created_at")
+	assert.Equal(t, s.Order, "desc")
+}
+
+func TestEmptySet(t *testing.T) {
+	s := New(Default()).EmptySet()
+	assert.Equal(t, s.Page, 1)
+	assert.Equal(t, s.PerPage, 10)
+	assert.Equal(t, s.Limit, 10)
+	assert.Equal(t, s.Offset, 0)
+	assert.Equal(t, s.Total, 0)
+	assert.Equal(t, s.TotalPages, 0)
+	assert.Equal(t, s.Pages, []int{})
+	assert.False(t, s.HasNext())
+	assert.False(t, s.HasPrev())
+}
+
+func TestDataAttributes(t *testing.T) {
+	o := Default()
+	o.NumPageNums = 3
+	o.ShowPrevNext = true
+	o.DataAttributes = true
+	p := New(o)
+
+	s := p.New(5, 10)
+	s.SetTotal(200)
+	out := s.HTML("/things", nil)
+	for _, n := range []int{1, 4, 6, 20} {
+		assert.Contains(t, out, fmt.Sprintf(`data-page="%d" href="/things?page=%d"`, n, n))
+	}
+	assert.Contains(t, out, `<a class="pg-page pg-selected" data-page="5" data-current="true" href="/things?page=5">5</a>`)
+	assert.Equal(t, strings.Count(out, "data-current"), 1)
+
+	// Disabled by default.
+	s = New(Default()).New(5, 10)
+	s.SetTotal(200)
+	assert.NotContains(t, s.HTML("/things", nil), "data-page")
+}
+
+func TestOmitPageOneParam(t *testing.T) {
+	o := Default()
+	o.OmitPageOneParam = true
+	p := New(o)
+
+	s := p.New(2, 10)
+	s.SetTotal(100)
+	assert.Equal(t, s.PageURL("/things", 1, nil), "/things")
+	assert.Equal(t, s.PageURL("/things", 2, nil), "/things?page=2")
+	assert.Equal(t, s.PageURL("/things", 1, url.Values{"q": []string{"a"}}), "/things?q=a")
+
+	// A page param already in the extra params is dropped too.
+	assert.Equal(t, s.PageURL("/things", 1, url.Values{"page": []string{"5"}}), "/things")
+
+	out := s.HTML("/things", nil)
+	assert.Contains(t, out, `href="/things">1</a>`)
+	assert.Contains(t, out, `href="/things?page=2">2</a>`)
+}
+
+func TestURLMode(t *testing.T) {
+	o := Default()
+	o.NumPageNums = 3
+	o.URLMode = URLModeFragment
+	p := New(o)
+
+	s := p.New(2, 10)
+	s.SetTotal(100)
+	assert.Equal(t, s.PageURL("/things", 3, url.Values{"q": []string{"a"}}), "/things#page=3&q=a")
+	assert.Contains(t, s.HTML("/things", nil), `href="/things#page=3">3</a>`)
+
+	o.URLMode = URLModeQuery
+	s = New(o).New(2, 10)
+	s.SetTotal(100)
+	assert.Equal(t, s.PageURL("/things", 3, nil), "/things?page=3")
+}
+
+func TestEmpty(t *testing.T) {
+	p := New(Default())
+
+	s := p.New(1, 10)
+	assert.True(t, s.Empty())
+
+	s.SetTotal(0)
+	assert.True(t, s.Empty())
+
+	s.SetTotal(5)
+	assert.False(t, s.Empty())
+}
+
+func TestCompactThreshold(t *testing.T) {
+	o := Default()
+	o.NumPageNums = 5
+	o.CompactThreshold = 100
+	p := New(o)
+
+	// At the threshold.
+	s := p.New(50, 10)
+	s.SetTotal(1000)
+	assert.Equal(t, s.Pages, []int{48, 49, 50, 51, 52})
+
+	// Above the threshold.
+	s = p.New(50, 10)
+	s.SetTotal(1010)
+	assert.Equal(t, s.Pages, []int{49, 50, 51})
+	assert.True(t, s.PinFirstPage)
+	assert.True(t, s.PinLastPage)
+	assert.Equal(t, s.Text(), "1 ... 49 [50] 51 ... 101")
+
+	// At the ends.
+	s = p.New(1, 10)
+	s.SetTotal(1010)
+	assert.Equal(t, s.Pages, []int{1, 2})
+	assert.False(t, s.PinFirstPage)
+
+	s = p.New(101, 10)
+	s.SetTotal(1010)
+	assert.Equal(t, s.Pages, []int{100, 101})
+	assert.False(t, s.PinLastPage)
+}
+
+func TestPerPageEcho(t *testing.T) {
+	p := New(Default())
+
+	q := url.Values{"page": []string{"2"}, "per_page": []string{"500"}, "q": []string{"a"}}
+	s := p.NewFromURL(q)
+	s.SetTotal(200)
+	assert.Equal(t, s.PerPage, 50)
+	assert.Equal(t, s.PageURL("/things", 3, q), "/things?page=3&per_page=50&q=a")
+	assert.Contains(t, s.HTML("/things", q), `href="/things?page=4&amp;per_page=50&amp;q=a"`)
+	assert.NotContains(t, s.HTML("/things", q), "per_page=500")
+
+	// A non-default per_page is added even without it in the params.
+	assert.Equal(t, s.PageURL("/things", 3, nil), "/things?page=3&per_page=50")
+
+	// The default per_page is not added.
+	s = p.New(2, 10)
+	s.SetTotal(200)
+	assert.Equal(t, s.PageURL("/things", 3, nil), "/things?page=3")
+
+	// Parse() strips per_page from the extra params, but it's carried over.
+	s, rest := p.Parse(url.Values{"page": []string{"2"}, "per_page": []string{"20"}, "q": []string{"x"}})
+	s.SetTotal(200)
+	assert.Contains(t, s.HTML("/things", rest), `href="/things?page=3&amp;per_page=20&amp;q=x"`)
+
+	// All.
+	o := Default()
+	o.AllowAll = true
+	q = url.Values{"per_page": []string{"all"}}
+	s = New(o).NewFromURL(q)
+	s.SetTotal(200)
+	assert.Equal(t, s.PageURL("/things", 1, q), "/things?page=1&per_page=all")
+
+	// The per_page options are not overridden.
+	s = p.NewFromURL(url.Values{"per_page": []string{"20"}})
+	assert.Contains(t, s.PerPageSelectHTML("/things", url.Values{"per_page": []string{"20"}}, []int{10}), `data-url="/things?page=1&amp;per_page=10"`)
+}
+
+func TestNamespace(t *testing.T) {
+	p := New(Default())
+	users := p.Namespace("users")
+	posts := p.Namespace("posts")
+
+	q := url.Values{
+		"users_page":     []string{"2"},
+		"users_per_page": []string{"5"},
+		"posts_page":     []string{"3"},
+		"page":           []string{"9"},
+	}
+
+	u := users.NewFromURL(q)
+	u.SetTotal(100)
+	assert.Equal(t, u.Page, 2)
+	assert.Equal(t, u.PerPage, 5)
+
+	ps := posts.NewFromURL(q)
+	ps.SetTotal(100)
+	assert.Equal(t, ps.Page, 3)
+	assert.Equal(t, ps.PerPage, 10)
+
+	assert.Equal(t, u.PageURL("/", 4, nil), "/?users_page=4&users_per_page=5")
+	assert.Contains(t, ps.HTML("/", nil), `href="/?posts_page=2"`)
+
+	// The original paginator is unaffected.
+	s := p.NewFromURL(q)
+	assert.Equal(t, s.Page, 9)
+}
+
+type mapCache map[string]int
+
+func (m mapCache) Get(key string) (int, bool) {
+	v, ok := m[key]
+	return v, ok
+}
+
+func (m mapCache) Set(key string, total int) {
+	m[key] = total
+}
+
+func TestResolveCached(t *testing.T) {
+	var (
+		cache = mapCache{}
+		calls = 0
+		count = func() (int, error) {
+			calls++
+			return 100, nil
+		}
+	)
+
+	o := Default()
+	o.TotalCache = cache
+	p := New(o)
+
+	// Miss.
+	s := p.New(2, 10)
+	assert.Nil(t, s.ResolveCached("things", count))
+	assert.Equal(t, s.Total, 100)
+	assert.Equal(t, s.TotalPages, 10)
+	assert.Equal(t, calls, 1)
+	assert.Equal(t, cache["things"], 100)
+
+	// Hit.
+	s = p.New(2, 10)
+	assert.Nil(t, s.ResolveCached("things", count))
+	assert.Equal(t, s.Total, 100)
+	assert.Equal(t, calls, 1)
+
+	// Errors are not cached.
+	s = p.New(2, 10)
+	err := s.ResolveCached("other", func() (int, error) { return 0, errors.New("fail") })
+	assert.Error(t, err)
+	_, ok := cache["other"]
+	assert.False(t, ok)
+
+	// No cache.
+	s = New(Default()).New(2, 10)
+	assert.Nil(t, s.ResolveCached("things", count))
+	assert.Equal(t, calls, 2)
+}
+
+func TestChaining(t *testing.T) {
+	o := Default()
+	o.NumPageNums = 3
+	p := New(o)
+
+	s := p.New(5, 10)
+	out := s.WithTotal(100).WithParams(url.Values{"q": []string{"a"}}).HTML("/things", nil)
+	assert.Equal(t, s.TotalPages, 10)
+	assert.Equal(t, s.Pages, []int{4, 5, 6})
+	assert.Equal(t, s.Params, url.Values{"q": []string{"a"}})
+	assert.Contains(t, out, `href="/things?page=6&amp;q=a"`)
+
+	// The total can be changed in the chain.
+	s.WithParams(nil).WithTotal(30)
+	assert.Equal(t, s.TotalPages, 3)
+	assert.Equal(t, s.Page, 3)
+}
+
+func TestJumpFormHTML(t *testing.T) {
+	p := New(Default())
+	s := p.New(3, 10)
+	s.SetTotal(95)
+
+	qp := url.Values{"q": []string{"a<b"}, "page": []string{"3"}, "sort": []string{"name"}}
+	assert.Equal(t, s.JumpFormHTML("/things", qp),
+		`<form class="pg-jump" method="get" action="/things">`+
+			`<input type="number" name="page" min="1" max="10" value="3">`+
+			`<input type="hidden" name="q" value="a&lt;b">`+
+			`<input type="hidden" name="sort" value="name">`+
+			`</form>`)
+	assert.Contains(t, s.JumpFormHTML("/things", nil), `max="10"`)
+
+	// No max without the total.
+	s = p.New(3, 10)
+	assert.Equal(t, s.JumpFormHTML("/things", nil),
+		`<form class="pg-jump" method="get" action="/things">`+
+			`<input type="number" name="page" min="1" value="3">`+
+			`</form>`)
+	// A non-default per_page is retained.
+	s = p.NewFromURL(url.Values{"page": []string{"2"}, "per_page": []string{"20"}})
+	s.SetTotal(95)
+	assert.Equal(t, s.JumpFormHTML("/things", nil),
+		`<form class="pg-jump" method="get" action="/things">`+
+			`<input type="number" name="page" min="1" max="5" value="2">`+
+			`<input type="hidden" name="per_page" value="20">`+
+			`</form>`)
+	o := Default()
+	o.AllowAll = true
+	s = New(o).NewFromURL(url.Values{"per_page": []string{"all"}})
+	assert.Contains(t, s.JumpFormHTML("/things", nil), `<input type="hidden" name="per_page" value="all">`)
+}
+
+func TestZeroMeansAll(t *testing.T) {
+	o := Default()
+	o.AllowAll = true
+	p := New(o)
+
+	for _, c := range []struct {
+		val string
+		exp int
+	}{
+		{"0", 10},
+		{"all", 0},
+		{"-1", 0},
+	} {
+		s := p.NewFromURL(url.Values{"per_page": []string{c.val}})
+		assert.Equal(t, s.PerPage, c.exp, c.val)
+	}
+
+	o.ZeroMeansAll = true
+	p = New(o)
+	for _, val := range []string{"0", "all", "-1"} {
+		s := p.NewFromURL(url.Values{"per_page": []string{val}})
+		assert.Equal(t, s.PerPage, 0, val)
+	}
+
+	// Not without AllowAll.
+	o.AllowAll = false
+	s := New(o).NewFromURL(url.Values{"per_page": []string{"0"}})
+	assert.Equal(t, s.PerPage, 10)
+}
+
+func TestOnParse(t *testing.T) {
+	var raw, san Set
+	calls := 0
+
+	o := Default()
+	o.OnParse = func(r, s Set) {
+		raw, san = r, s
+		calls++
+	}
+	p := New(o)
+
+	s := p.NewFromURL(url.Values{"page": []string{"-2"}, "per_page": []string{"500"}})
+	assert.Equal(t, calls, 1)
+	assert.Equal(t, raw.Page, -2)
+	assert.Equal(t, raw.PerPage, 500)
+	assert.Equal(t, raw.Limit, 500)
+	assert.Equal(t, san.Page, 1)
+	assert.Equal(t, san.PerPage, 50)
+	assert.Equal(t, san, s)
+
+	p.NewFromURL(url.Values{"per_page": []string{"-5"}})
+	assert.Equal(t, raw.PerPage, -5)
+	assert.Equal(t, san.PerPage, 10)
+
+	// Nil-safe.
+	assert.NotPanics(t, func() {
+		New(Default()).NewFromURL(url.Values{"page": []string{"2"}})
+	})
+}
+
+func TestTotalPagesFunc(t *testing.T) {
+	for _, c := range []struct {
+		total, perPage, exp int
+	}{
+		{0, 10, 0},
+		{1, 10, 1},
+		{10, 10, 1},
+		{11, 10, 2},
+		{95, 10, 10},
+		{0, 0, 0},
+		{95, 0, 1},
+	} {
+		assert.Equal(t, TotalPages(c.total, c.perPage), c.exp, c)
+	}
+}
+
+func TestBoundaryPages(t *testing.T) {
+	o := Default()
+	o.NumPageNums = 3
+	o.BoundaryPages = 2
+	p := New(o)
+
+	s := p.New(9, 10)
+	s.SetTotal(500)
+	assert.Equal(t, s.Pages, []int{1, 2, PageGap, 8, 9, 10, PageGap, 49, 50})
+	assert.False(t, s.PinFirstPage)
+	assert.False(t, s.PinLastPage)
+	assert.Equal(t, s.Text(), "1 2 ... 8 [9] 10 ... 49 50")
+
+	out := s.HTML("/things", nil)
+	assert.Contains(t, out, `<a class="pg-page" href="/things?page=2">2</a> <span class="pg-page-ellipsis-first">...</span> <a class="pg-page" href="/things?page=8">8</a>`)
+	assert.Contains(t, out, `<a class="pg-page" href="/things?page=10">10</a> <span class="pg-page-ellipsis-last">...</span> <a class="pg-page" href="/things?page=49">49</a>`)
+
+	// Overlapping boundaries have no gaps.
+	s = p.New(3, 10)
+	s.SetTotal(500)
+	assert.Equal(t, s.Pages, []int{1, 2, 3, 4, PageGap, 49, 50})
+
+	s = p.New(2, 10)
+	s.SetTotal(60)
+	assert.Equal(t, s.Pages, []int{1, 2, 3, 4, 5, 6})
+
+	// Descending.
+	o.DescendingPages = true
+	s = New(o).New(9, 10)
+	s.SetTotal(500)
+	assert.Equal(t, s.Pages, []int{50, 49, PageGap, 10, 9, 8, PageGap, 2, 1})
+	assert.Equal(t, s.Text(), "50 49 ... 10 [9] 8 ... 2 1")
+}
+
+func TestItems(t *testing.T) {
+	o := Default()
+	o.NumPageNums = 3
+	p := New(o)
+
+	s := p.New(5, 10)
+	s.SetTotal(200)
+	assert.Equal(t, s.Items, []PageItem{
+		{Num: 1}, {Ellipsis: true}, {Num: 4}, {Num: 5}, {Num: 6}, {Ellipsis: true}, {Num: 20},
+	})
+	assert.Equal(t, s.Pages, []int{4, 5, 6})
+
+	s = p.New(1, 10)
+	s.SetTotal(200)
+	assert.Equal(t, s.Items, []PageItem{
+		{Num: 1}, {Num: 2}, {Num: 3}, {Ellipsis: true}, {Num: 20},
+	})
+
+	// Boundary pages.
+	o.BoundaryPages = 1
+	s = New(o).New(9, 10)
+	s.SetTotal(500)
+	assert.Equal(t, s.Items, []PageItem{
+		{Num: 1}, {Ellipsis: true}, {Num: 8}, {Num: 9}, {Num: 10}, {Ellipsis: true}, {Num: 50},
+	})
+
+	s.SetTotal(0)
+	assert.Equal(t, s.Items, []PageItem{})
+}
+
+func TestValidate(t *testing.T) {
+	p := New(Default())
+
+	s := p.New(3, 10)
+	assert.Nil(t, s.Validate())
+	s.SetTotal(100)
+	assert.Nil(t, s.Validate())
+
+	// Offset.
+	bad := s
+	bad.Offset = 5
+	assert.ErrorIs(t, bad.Validate(), ErrInvalidSet)
+
+	// Page out of range.
+	bad = s
+	bad.Page = 11
+	bad.Offset = 100
+	assert.ErrorIs(t, bad.Validate(), ErrInvalidSet)
+
+	// Too many page numbers.
+	bad = s
+	bad.Pages = make([]int, 11)
+	assert.ErrorIs(t, bad.Validate(), ErrInvalidSet)
+
+	// Offsets that aren't multiples of the limit are valid.
+	s = p.NewFromOffsetLimit(45, 20)
+	assert.Nil(t, s.Validate())
+	s.SetTotal(100)
+	assert.Nil(t, s.Validate())
+
+	// Boundary pages with filled single page gaps.
+	o := Default()
+	o.BoundaryPages = 2
+	s = New(o).New(9, 10)
+	s.SetTotal(160)
+	assert.Equal(t, len(s.Pages), 16)
+	assert.Nil(t, s.Validate())
+
+	// Boundary pages with the compact series.
+	o = Default()
+	o.NumPageNums = 1
+	o.BoundaryPages = 1
+	o.CompactThreshold = 3
+	s = New(o).New(3, 10)
+	s.SetTotal(56)
+	assert.Nil(t, s.Validate())
+
+	// No paginator.
+	assert.ErrorIs(t, (&Set{}).Validate(), ErrInvalidSet)
+}
+
+func TestQueryString(t *testing.T) {
+	p := New(Default())
+
+	q := url.Values{"page": []string{"20"}, "per_page": []string{"500"}, "q": []string{"a"}}
+	s := p.NewFromURL(q)
+	s.SetTotal(200)
+	assert.Equal(t, s.QueryString(q), "page=4&per_page=50&q=a")
+	assert.Equal(t, s.QueryString(nil), "page=4&per_page=50")
+	assert.Equal(t, q.Get("page"), "20")
+
+	o := Default()
+	o.AllowAll = true
+	s = New(o).NewFromURL(url.Values{"per_page": []string{"all"}})
+	assert.Equal(t, s.QueryString(nil), "page=1&per_page=all")
+}
+
+func TestURLBuilder(t *testing.T) {
+	o := Default()
+	o.NumPageNums = 3
+	o.URLBuilder = func(base string, page int, qp url.Values) string {
+		u := base + "/page/" + strconv.Itoa(page)
+		if len(qp) > 0 {
+			u += "?" + qp.Encode()
+		}
+		return u
+	}
+	p := New(o)
+
+	s := p.New(1, 10)
+	s.SetTotal(100)
+	assert.Equal(t, s.PageURL("/things", 2, nil), "/things/page/2")
+	assert.Equal(t, s.PageURL("/things", 2, url.Values{"q": []string{"a"}}), "/things/page/2?q=a")
+	assert.Contains(t, s.HTML("/things", nil), `<a class="pg-page" href="/things/page/2">2</a>`)
+}
+
+type mockLimiter struct {
+	calls []string
+}
+
+func (m *mockLimiter) Offset(n int) {
+	m.calls = append(m.calls, fmt.Sprintf("offset %d", n))
+}
+
+func (m *mockLimiter) Limit(n int) {
+	m.calls = append(m.calls, fmt.Sprintf("limit %d", n))
+}
+
+func TestApply(t *testing.T) {
+	var q mockLimiter
+	s := New(Default()).New(3, 20)
+	s.Apply(&q)
+	assert.Equal(t, q.calls, []string{"offset 40", "limit 20"})
+
+	// All.
+	o := Default()
+	o.AllowAll = true
+	q = mockLimiter{}
+	s = New(o).New(1, -1)
+	s.Apply(&q)
+	assert.Equal(t, q.calls, []string{"offset 0"})
+}
+
+func TestPerPageHeader(t *testing.T) {
+	o := Default()
+	o.PerPageHeader = "X-Per-Page"
+	p := New(o)
+
+	// Header.
+	r := httptest.NewRequest(http.MethodGet, "/things?page=2", nil)
+	r.Header.Set("X-Per-Page", "25")
+	s := p.NewFromRequest(r)
+	assert.Equal(t, s.Page, 2)
+	assert.Equal(t, s.PerPage, 25)
+	assert.Equal(t, r.URL.Query().Get("per_page"), "")
+
+	// The query param overrides the header.
+	r = httptest.NewRequest(http.MethodGet, "/things?page=2&per_page=15", nil)
+	r.Header.Set("X-Per-Page", "25")
+	s = p.NewFromRequest(r)
+	assert.Equal(t, s.PerPage, 15)
+
+	// The header is ignored if PerPageHeader is not set.
+	r = httptest.NewRequest(http.MethodGet, "/things", nil)
+	r.Header.Set("X-Per-Page", "25")
+	s = New(Default()).NewFromRequest(r)
+	assert.Equal(t, s.PerPage, 10)
+}
+
+func TestLinkSeparator(t *testing.T) {
+	o := Default()
+	o.NumPageNums = 3
+	o.LinkSeparator = "|"
+	p := New(o)
+
+	s := p.New(1, 10)
+	s.SetTotal(30)
+	assert.Equal(t, s.HTML("/things", nil),
+		`<a class="pg-page pg-selected" href="/things?page=1">1</a>|`+
+			`<a class="pg-page" href="/things?page=2">2</a>|`+
+			`<a class="pg-page" href="/things?page=3">3</a>`)
+
+	// Prev/next and ellipses.
+	o.ShowPrevNext = true
+	o.Accessible = true
+	s = New(o).New(5, 10)
+	s.SetTotal(100)
+	out := s.HTML("/things", nil)
+	assert.True(t, strings.HasPrefix(out, `<nav aria-label="Pagination"><a class="pg-prev" aria-label="Previous page" href="/things?page=4">«</a>|`+
+		`<a class="pg-page-first" href="/things?page=1">1</a>|<span class="pg-page-ellipsis-first">...</span>|`))
+	assert.True(t, strings.HasSuffix(out, `|<a class="pg-next" aria-label="Next page" href="/things?page=6">»</a></nav>`))
+
+	// No separator.
+	o.ShowPrevNext = false
+	o.Accessible = false
+	o.NoLinkSeparator = true
+	s = New(o).New(1, 10)
+	s.SetTotal(20)
+	assert.Equal(t, s.HTML("/things", nil),
+		`<a class="pg-page pg-selected" href="/things?page=1">1</a>`+
+			`<a class="pg-page" href="/things?page=2">2</a>`)
+
+	// An empty separator defaults to a space.
+	s = New(Opt{DefaultPerPage: 10, MaxPerPage: 50, NumPageNums: 3, PageParam: "page"}).New(1, 10)
+	s.SetTotal(20)
+	assert.Equal(t, s.HTML("/things", nil),
+		`<a class="pg-page pg-selected" href="/things?page=1">1</a> `+
+			`<a class="pg-page" href="/things?page=2">2</a>`)
+}
+
+func TestLastPageOffset(t *testing.T) {
+	p := New(Default())
+
+	s := p.New(1, 10)
+	assert.Equal(t, s.LastPageOffset(), 0)
+
+	for _, c := range []struct {
+		total, exp int
+	}{
+		{0, 0},
+		{5, 0},
+		{10, 0},
+		{11, 10},
+		{95, 90},
+		{100, 90},
+	} {
+		s.SetTotal(c.total)
+		assert.Equal(t, s.LastPageOffset(), c.exp, c.total)
+	}
+
+	// Zero indexed.
+	o := Default()
+	o.ZeroIndexed = true
+	s = New(o).New(0, 10)
+	s.SetTotal(95)
+	assert.Equal(t, s.LastPageOffset(), 90)
+}
+
+func TestWindowRadius(t *testing.T) {
+	r := Default()
+	r.WindowRadius = 2
+	r.NumPageNums = 10
+
+	n := Default()
+	n.NumPageNums = 5
+
+	for _, page := range []int{1, 2, 5, 10, 19, 20} {
+		a := New(r).New(page, 10)
+		a.SetTotal(200)
+		b := New(n).New(page, 10)
+		b.SetTotal(200)
+		assert.Equal(t, a.Pages, b.Pages, page)
+		assert.Equal(t, len(a.Pages), 5)
+		assert.Nil(t, a.Validate())
+	}
+
+	s := New(r).New(10, 10)
+	s.SetTotal(200)
+	assert.Equal(t, s.Pages, []int{8, 9, 10, 11, 12})
+}
+
+func TestHTMLHasMore(t *testing.T) {
+	p := New(Default())
+
+	s := p.New(3, 10)
+	s.SetHasMore(true)
+	assert.Equal(t, s.HTML("/things", nil),
+		`<a class="pg-prev" href="/things?page=2">«</a> `+
+			`<a class="pg-page pg-selected" href="/things?page=3">3</a> `+
+			`<a class="pg-next" href="/things?page=4">»</a>`)
+
+	// No more.
+	s = p.New(1, 10)
+	s.SetHasMore(false)
+	assert.Equal(t, s.HTML("/things", nil),
+		`<span class="pg-prev pg-disabled">«</span> `+
+			`<a class="pg-page pg-selected" href="/things?page=1">1</a> `+
+			`<span class="pg-next pg-disabled">»</span>`)
+
+	// Without SetHasMore(), nothing is rendered.
+	s = p.New(3, 10)
+	assert.Equal(t, s.HTML("/things", nil), "")
+}
+
+func TestMaxTotal(t *testing.T) {
+	o := Default()
+	o.MaxTotal = 1000
+	p := New(o)
+
+	s := p.New(500, 10)
+	s.SetTotal(1000000)
+	assert.Equal(t, s.Total, 1000)
+	assert.Equal(t, s.RealTotal, 1000000)
+	assert.Equal(t, s.TotalPages, 100)
+	assert.Equal(t, s.Page, 100)
+	assert.False(t, s.HasNext())
+
+	// Below the cap.
+	s = p.New(1, 10)
+	s.SetTotal(95)
+	assert.Equal(t, s.Total, 95)
+	assert.Equal(t, s.RealTotal, 95)
+	assert.Equal(t, s.TotalPages, 10)
+
+	// No cap.
+	s = New(Default()).New(1, 10)
+	s.SetTotal(1000000)
+	assert.Equal(t, s.TotalPages, 100000)
+}